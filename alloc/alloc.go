@@ -0,0 +1,294 @@
+package alloc
+
+import (
+	"errors"
+	"sync"
+
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/internal/value"
+)
+
+// Policy decides where the search for a free value begins.
+type Policy int
+
+const (
+	// FirstFit always hands out the lowest free value.
+	FirstFit Policy = iota
+	// NextFit continues searching from the last allocation, wrapping around at the end of the universe.
+	// It spreads allocations out, which avoids reusing recently-freed values.
+	NextFit
+)
+
+var (
+	ErrFull         = errors.New("alloc: no free values")
+	ErrOutOfRange   = errors.New("alloc: value is outside of the allocator's universe")
+	ErrAllocated    = errors.New("alloc: value is already allocated")
+	ErrNotAllocated = errors.New("alloc: value is not allocated")
+)
+
+const wordBits = 64
+
+// Allocator hands out unique values from a bounded universe, [0, size).
+//
+// Allocated values are tracked in a dense bitset. On top of that, a hierarchy of summary bitsets marks which words are full:
+// a bit in level 0 is set when its word is full, and a bit in each level above is set when its word in the level below is full.
+// Searching for a free value descends through the summaries, so it stays fast even when the universe is nearly full.
+type Allocator[V bitset.Value] struct {
+	lock *sync.Mutex
+
+	used   *bits.Bitset[uint64, V]
+	counts []uint8    // number of allocated values, per word of used
+	levels [][]uint64 // full-word summaries. the last level is a single word.
+
+	size   uint
+	policy Policy
+	next   uint // where NextFit resumes
+}
+
+// New creates an allocator for the values [0, size). size is clamped to the number of values V can hold, so that
+// none is handed out twice.
+func New[V bitset.Value](size uint, policy Policy) *Allocator[V] {
+	if max := uint(value.Max[V]()); size > max {
+		size = max + 1
+	}
+	words := (size + wordBits - 1) / wordBits
+
+	a := &Allocator[V]{
+		lock:   &sync.Mutex{},
+		used:   bits.New[uint64, V](size),
+		counts: make([]uint8, words),
+		size:   size,
+		policy: policy,
+	}
+
+	// bits past the end of each level don't refer to anything, so they're permanently full.
+	for n := words; ; {
+		level := make([]uint64, (n+wordBits-1)/wordBits)
+		if len(level) == 0 {
+			level = make([]uint64, 1)
+		}
+		for i := n; i < uint(len(level))*wordBits; i++ {
+			level[i/wordBits] |= 1 << (i % wordBits)
+		}
+		a.levels = append(a.levels, level)
+
+		if len(level) == 1 {
+			break
+		}
+		n = uint(len(level))
+	}
+
+	return a
+}
+
+// Size is the number of values in the universe.
+func (a *Allocator[V]) Size() uint {
+	return a.size
+}
+
+// Available is the number of values which can still be allocated.
+func (a *Allocator[V]) Available() uint {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.size - a.used.Pop()
+}
+
+// Allocated reports whether v is currently allocated.
+func (a *Allocator[V]) Allocated(v V) bool {
+	return a.used.Get(v)
+}
+
+// Allocate hands out a single free value, chosen according to the allocator's policy.
+func (a *Allocator[V]) Allocate() (V, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	v, ok := a.findFree(a.start())
+	if !ok && a.policy == NextFit {
+		v, ok = a.findFree(0)
+	}
+	if !ok {
+		return 0, ErrFull
+	}
+
+	a.mark(v, 1)
+	return V(v), nil
+}
+
+// AllocateN hands out n contiguous free values, returning the first of them.
+func (a *Allocator[V]) AllocateN(n uint) (V, error) {
+	if n == 0 {
+		return 0, nil
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	v, ok := a.findRun(a.start(), n)
+	if !ok && a.policy == NextFit {
+		v, ok = a.findRun(0, n)
+	}
+	if !ok {
+		return 0, ErrFull
+	}
+
+	a.mark(v, n)
+	return V(v), nil
+}
+
+// AllocateAt allocates a specific value.
+func (a *Allocator[V]) AllocateAt(v V) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if uint(v) >= a.size {
+		return ErrOutOfRange
+	}
+	if a.used.Get(v) {
+		return ErrAllocated
+	}
+
+	a.mark(uint(v), 1)
+	return nil
+}
+
+// Free releases an allocated value, so that it can be handed out again.
+func (a *Allocator[V]) Free(v V) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if uint(v) >= a.size {
+		return ErrOutOfRange
+	}
+	if !a.used.Get(v) {
+		return ErrNotAllocated
+	}
+
+	w := uint(v) / wordBits
+	if a.counts[w] == a.wordCap(w) {
+		a.setFull(0, w, false)
+	}
+	a.counts[w]--
+	a.used.Unset(v)
+
+	return nil
+}
+
+// start is where the search for a free value begins.
+func (a *Allocator[V]) start() uint {
+	if a.policy == NextFit {
+		return a.next
+	}
+	return 0
+}
+
+// mark allocates [v, v+n), which must all be free.
+func (a *Allocator[V]) mark(v, n uint) {
+	vals := make([]V, n)
+	for i := range vals {
+		vals[i] = V(v + uint(i))
+
+		w := (v + uint(i)) / wordBits
+		a.counts[w]++
+		if a.counts[w] == a.wordCap(w) {
+			a.setFull(0, w, true)
+		}
+	}
+	a.used.Set(vals...)
+
+	a.next = v + n
+	if a.next >= a.size {
+		a.next = 0
+	}
+}
+
+// wordCap is the number of values within the universe covered by word w.
+func (a *Allocator[V]) wordCap(w uint) uint8 {
+	if rest := a.size - w*wordBits; rest < wordBits {
+		return uint8(rest)
+	}
+	return wordBits
+}
+
+// setFull updates the summary bit i at the given level, propagating upwards whenever a summary word fills up or stops being full.
+func (a *Allocator[V]) setFull(level int, i uint, full bool) {
+	word := &a.levels[level][i/wordBits]
+	wasFull := *word == ^uint64(0)
+
+	if full {
+		*word |= 1 << (i % wordBits)
+	} else {
+		*word &^= 1 << (i % wordBits)
+	}
+
+	if isFull := *word == ^uint64(0); isFull != wasFull && level+1 < len(a.levels) {
+		a.setFull(level+1, i/wordBits, isFull)
+	}
+}
+
+// nonFull finds the first summary bit at the given level, at or after i, whose word isn't full.
+// Only one word is examined per level.
+func (a *Allocator[V]) nonFull(level int, i uint) (uint, bool) {
+	sum := a.levels[level]
+	if i/wordBits >= uint(len(sum)) {
+		return 0, false
+	}
+	if word := ^sum[i/wordBits] >> (i % wordBits); word != 0 {
+		return i + uint(mb.TrailingZeros64(word)), true
+	}
+	if level+1 == len(a.levels) {
+		// the top level is a single word
+		return 0, false
+	}
+
+	j, ok := a.nonFull(level+1, i/wordBits+1)
+	if !ok {
+		return 0, false
+	}
+	return j*wordBits + uint(mb.TrailingZeros64(^sum[j])), true
+}
+
+// findFree finds the first free value at or after from.
+func (a *Allocator[V]) findFree(from uint) (uint, bool) {
+	if from >= a.size {
+		return 0, false
+	}
+
+	w := from / wordBits
+	if a.counts[w] < a.wordCap(w) {
+		// there's space in this word, but it may be behind from
+		if v, ok := a.used.NextClear(V(from)); ok && uint(v) < (w+1)*wordBits && uint(v) < a.size {
+			return uint(v), true
+		}
+	}
+
+	w, ok := a.nonFull(0, w+1)
+	if !ok {
+		return 0, false
+	}
+	v, _ := a.used.NextClear(V(w * wordBits))
+	return uint(v), true
+}
+
+// findRun finds the first run of n free values which starts at or after from.
+func (a *Allocator[V]) findRun(from, n uint) (uint, bool) {
+	for {
+		start, ok := a.findFree(from)
+		if !ok || start+n > a.size {
+			return 0, false
+		}
+
+		end := a.size
+		if next, ok := a.used.NextSet(V(start)); ok && uint(next) < end {
+			end = uint(next)
+		}
+		if end-start >= n {
+			return start, true
+		}
+		from = end
+	}
+}
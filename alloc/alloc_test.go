@@ -0,0 +1,148 @@
+package alloc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocate_FirstFit(t *testing.T) {
+	a := New[uint16](130, FirstFit)
+
+	for i := uint16(0); i < 130; i++ {
+		v, err := a.Allocate()
+		assert.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+	_, err := a.Allocate()
+	assert.ErrorIs(t, err, ErrFull)
+	assert.Equal(t, uint(0), a.Available())
+
+	assert.NoError(t, a.Free(70))
+	assert.NoError(t, a.Free(3))
+	assert.ErrorIs(t, a.Free(3), ErrNotAllocated)
+	assert.ErrorIs(t, a.Free(130), ErrOutOfRange)
+
+	v, err := a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(3), v)
+
+	v, err = a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(70), v)
+}
+
+func TestAllocate_NextFit(t *testing.T) {
+	a := New[uint](10, NextFit)
+
+	for i := uint(0); i < 5; i++ {
+		_, _ = a.Allocate()
+	}
+	assert.NoError(t, a.Free(1))
+
+	v, err := a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), v)
+
+	assert.NoError(t, a.AllocateAt(9))
+	// wraps around
+	v, err = a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), v)
+}
+
+func TestAllocateAt(t *testing.T) {
+	a := New[uint](100, FirstFit)
+
+	assert.NoError(t, a.AllocateAt(0))
+	assert.ErrorIs(t, a.AllocateAt(0), ErrAllocated)
+	assert.ErrorIs(t, a.AllocateAt(100), ErrOutOfRange)
+	assert.True(t, a.Allocated(0))
+	assert.False(t, a.Allocated(1))
+}
+
+func TestAllocateN(t *testing.T) {
+	a := New[uint](200, FirstFit)
+
+	for _, v := range []uint{2, 10, 60, 140} {
+		assert.NoError(t, a.AllocateAt(v))
+	}
+
+	v, err := a.AllocateN(7)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), v)
+
+	// spans a word boundary
+	v, err = a.AllocateN(70)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(61), v)
+	for i := uint(61); i < 131; i++ {
+		assert.True(t, a.Allocated(i))
+	}
+
+	_, err = a.AllocateN(60)
+	assert.ErrorIs(t, err, ErrFull)
+
+	v, err = a.AllocateN(59)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(141), v)
+}
+
+func TestSummary(t *testing.T) {
+	// three levels: 4993 words, 79 level 0 words, 2 level 1 words, and 1 at the top.
+	const size = 64*64*64 + 64*64*14 + 5
+	a := New[uint32](size, FirstFit)
+	assert.Len(t, a.levels, 3)
+
+	for i := uint32(0); i < size; i++ {
+		if i != 300_000 {
+			assert.NoError(t, a.AllocateAt(i))
+		}
+	}
+	v, err := a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(300_000), v)
+
+	_, err = a.Allocate()
+	assert.ErrorIs(t, err, ErrFull)
+
+	// everything is full, all the way up
+	assert.Equal(t, ^uint64(0), a.levels[2][0])
+
+	assert.NoError(t, a.Free(size-1))
+	assert.NoError(t, a.Free(4097))
+	assert.NotEqual(t, ^uint64(0), a.levels[2][0])
+
+	v, err = a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(4097), v)
+
+	v, err = a.Allocate()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(size-1), v)
+}
+
+func TestEmpty(t *testing.T) {
+	a := New[uint](0, FirstFit)
+
+	_, err := a.Allocate()
+	assert.ErrorIs(t, err, ErrFull)
+	_, err = a.AllocateN(1)
+	assert.ErrorIs(t, err, ErrFull)
+}
+
+func TestSizeClamped(t *testing.T) {
+	a := New[uint8](300, FirstFit)
+	assert.Equal(t, uint(256), a.Size())
+
+	seen := map[uint8]bool{}
+	for i := 0; i < 256; i++ {
+		v, err := a.Allocate()
+		assert.NoError(t, err)
+		assert.False(t, seen[v], "%d is handed out twice", v)
+		seen[v] = true
+	}
+	_, err := a.Allocate()
+	assert.ErrorIs(t, err, ErrFull)
+	assert.Zero(t, a.Available())
+}
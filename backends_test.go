@@ -0,0 +1,198 @@
+package bitset_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/bools"
+	"github.com/zblach/go-bitset/internal/backends"
+	"github.com/zblach/go-bitset/iterable"
+	mapset "github.com/zblach/go-bitset/sparse/map"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+// updater is a backend with transactions. persistent's, which returns a new version, is tested on its own.
+type updater interface {
+	bitset.Bitset[uint]
+	iterable.Iterable[uint]
+	Update(fn func(tx bitset.Tx[uint]) error) error
+}
+
+func TestUpdate(t *testing.T) {
+	for name, mk := range backends.WithFixed[uint]() {
+		s := mk().(updater)
+		s.Set(1, 2, 3)
+
+		err := s.Update(func(tx bitset.Tx[uint]) error {
+			tx.Unset(2)
+			tx.SetRange(10, 12)
+			tx.Set(200)
+			assert.False(t, tx.Get(2), name)
+			assert.True(t, tx.Get(11), name)
+			assert.True(t, tx.Get(3), name)
+			return nil
+		})
+		assert.NoError(t, err, name)
+		assert.Equal(t, []uint{1, 3, 10, 11, 12, 200}, iterable.Values[uint](s), name)
+
+		invalid := errors.New("invalid")
+		err = s.Update(func(tx bitset.Tx[uint]) error {
+			tx.UnsetRange(0, 100)
+			tx.Set(50)
+			return invalid
+		})
+		assert.ErrorIs(t, err, invalid, name)
+		assert.Equal(t, []uint{1, 3, 10, 11, 12, 200}, iterable.Values[uint](s), "%s: nothing is applied", name)
+
+		err = s.Update(func(tx bitset.Tx[uint]) error {
+			tx.UnsetRange(0, 100)
+			tx.Set(50)
+			return nil
+		})
+		assert.NoError(t, err, name)
+		assert.Equal(t, []uint{50, 200}, iterable.Values[uint](s), name)
+	}
+}
+
+// waiter is a backend which can be waited on. Every one which locks is, so only fixed isn't.
+type waiter interface {
+	updater
+	WaitFor(ctx context.Context, v uint) error
+	WaitAll(ctx context.Context, vs ...uint) error
+	WaitAny(ctx context.Context, vs ...uint) (uint, error)
+}
+
+func TestWait(t *testing.T) {
+	for name, mk := range backends.WithFixed[uint]() {
+		s, ok := mk().(waiter)
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		goroutines := runtime.NumGoroutine()
+
+		// single values, and ranges, wake waiters
+		go func() {
+			time.Sleep(time.Millisecond)
+			s.Set(5)
+			assert.NoError(t, s.Update(func(tx bitset.Tx[uint]) error {
+				tx.SetRange(10, 20)
+				return nil
+			}))
+		}()
+
+		assert.NoError(t, s.WaitFor(ctx, 5), name)
+		assert.NoError(t, s.WaitAll(ctx, 5, 10, 20), name)
+		v, err := s.WaitAny(ctx, 3, 15)
+		assert.NoError(t, err, name)
+		assert.Equal(t, uint(15), v, name)
+		assert.NoError(t, s.WaitAll(ctx), "%s: nothing to wait for", name)
+
+		short, stop := context.WithTimeout(ctx, 10*time.Millisecond)
+		assert.ErrorIs(t, s.WaitFor(short, 3), context.DeadlineExceeded, name)
+		_, err = s.WaitAny(short)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, name)
+		assert.NoError(t, s.WaitFor(short, 5), "%s: already a member", name)
+		stop()
+
+		cancel()
+		assert.ErrorIs(t, s.WaitFor(ctx, 3), context.Canceled, name)
+		for i := 0; i < 1000 && runtime.NumGoroutine() > goroutines; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines, "%s: nothing is left waiting", name)
+	}
+}
+
+// snapshot is what every backend's Snapshot returns.
+type snapshot interface {
+	iterable.Iterable[uint]
+	Get(v uint) bool
+	Pop() uint
+}
+
+// snapshotters returns, for every backend with snapshots, a new set and a function which takes one of it.
+func snapshotters() map[string]func() (bitset.Bitset[uint], func() snapshot) {
+	return map[string]func() (bitset.Bitset[uint], func() snapshot){
+		"bits": func() (bitset.Bitset[uint], func() snapshot) {
+			s := bits.New[uint64, uint](0)
+			return s, func() snapshot { return s.Snapshot() }
+		},
+		"bools": func() (bitset.Bitset[uint], func() snapshot) {
+			s := bools.New[uint](0)
+			return s, func() snapshot { return s.Snapshot() }
+		},
+		"map": func() (bitset.Bitset[uint], func() snapshot) {
+			s := mapset.New[uint]()
+			return s, func() snapshot { return s.Snapshot() }
+		},
+		"range": func() (bitset.Bitset[uint], func() snapshot) {
+			s := rangeset.New[uint]()
+			return s, func() snapshot { return s.Snapshot() }
+		},
+	}
+}
+
+func TestSnapshotConcurrent(t *testing.T) {
+	for name, mk := range snapshotters() {
+		s, take := mk()
+		done := make(chan struct{})
+
+		// values are set and unset in pairs, so every snapshot has an even population
+		go func() {
+			defer close(done)
+			for i := uint(0); i < 2000; i++ {
+				v := i * 37 % 100000
+				s.Set(v, v+100000)
+				if i%3 == 0 {
+					s.Unset(v, v+100000)
+				}
+				if tr, ok := s.(interface{ Trim() }); ok && i%500 == 0 {
+					tr.Trim()
+				}
+			}
+		}()
+
+		for running := true; running; {
+			select {
+			case <-done:
+				running = false
+			default:
+			}
+			snap := take()
+			vals := iterable.Values[uint](snap)
+			assert.Len(t, vals, int(snap.Pop()), name)
+			assert.Zero(t, snap.Pop()%2, name)
+			for _, v := range vals {
+				assert.True(t, snap.Get(v), name)
+			}
+		}
+	}
+}
+
+func TestPageAfter(t *testing.T) {
+	for name, mk := range backends.All[uint]() {
+		t.Run(name, func(t *testing.T) {
+			s := mk()
+			p, ok := s.(bitset.Pager[uint])
+			if !ok {
+				t.Skip("no pages")
+			}
+			s.Set(1, 4, 9, 16, 25)
+
+			vals, next, done := bitset.PageAfter(p, 4, 2)
+			assert.Equal(t, []uint{9, 16}, vals)
+			assert.False(t, done)
+
+			vals, _, done = p.Page(next, 2)
+			assert.Equal(t, []uint{25}, vals)
+			assert.True(t, done)
+		})
+	}
+}
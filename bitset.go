@@ -26,15 +26,44 @@ type Binary[V Value, S Bitset[V]] interface {
 	Or(b S) (aOrB S)   // self
 }
 
-// Size-related inspection referring to the underlying bitset data storage
+// Size-related inspection referring to the underlying bitset data storage.
+//
+// Len is the storage in use, and Cap the storage allocated, both counted in the backend's own unit: values for the
+// dense backends (bits, bools, hier, window and fixed) and persistent's leaves, ranges for rangeset, and entries for
+// mapset. Neither is the number of members, which is Pop. Inspect.Bytes measures storage in a unit which is
+// comparable between backends.
 type Size interface {
 	// int and not uint for consistency's sake :(
 	Len() int
 	Cap() int
 }
 
+// Capacity is implemented by backends which manage their storage explicitly.
+// Len reflects the storage in use, and Cap what's allocated, after each of these.
+type Capacity interface {
+	Size
+
+	// Trim drops trailing empty storage, and releases the capacity beyond what's left.
+	Trim()
+	// Reserve preallocates storage for values below n, without changing Len.
+	Reserve(n uint)
+	// Reset unsets every value, keeping the allocated storage for reuse.
+	Reset()
+	// AutoShrink trims storage as values are unset, and releases it once less than the fraction below is in use.
+	// Zero, the default, disables it. DefaultShrink is a reasonable threshold.
+	AutoShrink(below float64)
+}
+
+// DefaultShrink releases storage once less than a quarter of it is in use.
+const DefaultShrink = 0.25
+
 // Population count & size. TODO: move 'Pop' to Bitset proper?
 type Inspect[V Value] interface {
 	Size
 	Pop() uint
+
+	// Bytes estimates the heap footprint of the set, including its fixed overhead.
+	Bytes() int
+	// Stats summarizes the members of the set, along with its Bytes.
+	Stats() Stats[V]
 }
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options control what's generated.
+type Options struct {
+	Type  string // name of the constants' type
+	Set   string // name of the generated set type; defaults to <Type>Set
+	Dense bool   // back the set with dense/bits, instead of dense/fixed
+	Skip  string // file to ignore while loading the package, usually the previous output
+}
+
+// value is a named constant of the set's type.
+type value struct {
+	Name  string
+	Value uint64
+}
+
+// group is a named subset, declared with a //bitset:group directive.
+type group struct {
+	Name    string
+	Func    string
+	Members []string
+}
+
+// model is everything the template needs.
+type model struct {
+	Args    string
+	Package string
+	Type    string
+	Set     string
+	Dense   bool
+	Fixed   string // Set64, Set128 or Set256
+
+	New, Parse, All, None string
+
+	Values []value // every constant, in declaration order
+	Unique []value // the first constant for each value, in ascending order
+	Groups []group
+}
+
+const directive = "//bitset:group"
+
+// Generate loads the non-test files of the package in dir, and returns the formatted source of a set of opts.Type.
+func Generate(dir string, opts Options) ([]byte, error) {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, dir, opts.Skip)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	// errors are ignored, as long as the constants themselves can be evaluated. typically, they're due to unresolved imports.
+	conf := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, nil)
+
+	typ, ok := pkg.Scope().Lookup(opts.Type).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", opts.Type, dir)
+	}
+	if b, ok := typ.Type().Underlying().(*types.Basic); !ok || (b.Info()&types.IsUnsigned == 0 && b.Kind() != types.Int32) {
+		return nil, fmt.Errorf("type %s is not a bitset.Value; it must be an unsigned integer or rune", opts.Type)
+	}
+
+	m := &model{
+		Args:    args(opts),
+		Package: pkg.Name(),
+		Type:    opts.Type,
+		Set:     opts.Set,
+		Dense:   opts.Dense,
+	}
+	if m.Set == "" {
+		m.Set = opts.Type + "Set"
+	}
+	m.New = exported(m.Set, "new") + upperFirst(m.Set)
+	m.Parse = exported(m.Set, "parse") + upperFirst(m.Set)
+	m.All = m.Set + "All"
+	m.None = m.Set + "None"
+
+	if m.Values, err = constants(fset, pkg, typ); err != nil {
+		return nil, err
+	}
+	if len(m.Values) == 0 {
+		return nil, fmt.Errorf("no constants of type %s", opts.Type)
+	}
+	m.Unique = unique(m.Values)
+
+	if !m.Dense {
+		switch max := m.Unique[len(m.Unique)-1]; {
+		case max.Value < 64:
+			m.Fixed = "Set64"
+		case max.Value < 128:
+			m.Fixed = "Set128"
+		case max.Value < 256:
+			m.Fixed = "Set256"
+		default:
+			return nil, fmt.Errorf("%s = %d doesn't fit in a fixed set; use -dense", max.Name, max.Value)
+		}
+	}
+
+	if m.Groups, err = groups(files, m); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// parseDir parses the non-test Go files in dir, except for skip.
+func parseDir(fset *token.FileSet, dir, skip string) ([]*ast.File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || (skip != "" && filepath.Clean(path) == filepath.Clean(skip)) {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// constants returns the package-level constants of type typ, in declaration order.
+func constants(fset *token.FileSet, pkg *types.Package, typ *types.TypeName) ([]value, error) {
+	var objs []*types.Const
+	for _, name := range pkg.Scope().Names() {
+		if c, ok := pkg.Scope().Lookup(name).(*types.Const); ok && name != "_" && types.Identical(c.Type(), typ.Type()) {
+			objs = append(objs, c)
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		pi, pj := fset.Position(objs[i].Pos()), fset.Position(objs[j].Pos())
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+
+	vals := make([]value, 0, len(objs))
+	for _, c := range objs {
+		v, exact := constant.Uint64Val(constant.ToInt(c.Val()))
+		if !exact {
+			return nil, fmt.Errorf("%s = %s isn't a valid set member", c.Name(), c.Val())
+		}
+		vals = append(vals, value{Name: c.Name(), Value: v})
+	}
+	return vals, nil
+}
+
+// unique drops constants which alias an earlier one, and sorts the rest by value.
+func unique(vals []value) []value {
+	seen := map[uint64]bool{}
+	var u []value
+	for _, v := range vals {
+		if !seen[v.Value] {
+			seen[v.Value] = true
+			u = append(u, v)
+		}
+	}
+	sort.SliceStable(u, func(i, j int) bool { return u[i].Value < u[j].Value })
+	return u
+}
+
+// groups collects the //bitset:group directives which refer to the model's constants.
+// Directives naming constants of some other type are ignored, so several sets can be generated for one package.
+func groups(files []*ast.File, m *model) ([]group, error) {
+	known := map[string]bool{}
+	for _, v := range m.Values {
+		known[v.Name] = true
+	}
+
+	var gs []group
+	seen := map[string]bool{}
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				if !strings.HasPrefix(c.Text, directive) {
+					continue
+				}
+				rest := strings.TrimPrefix(c.Text, directive)
+				if rest != "" && !unicode.IsSpace(rune(rest[0])) {
+					continue
+				}
+
+				fields := strings.Fields(rest)
+				if len(fields) < 2 {
+					return nil, fmt.Errorf("malformed directive %q; want %s name A,B,...", c.Text, directive)
+				}
+
+				g := group{Name: fields[0], Func: m.Set + camel(fields[0])}
+				var foreign []string
+				for _, name := range strings.FieldsFunc(strings.Join(fields[1:], ""), func(r rune) bool { return r == ',' }) {
+					if known[name] {
+						g.Members = append(g.Members, name)
+					} else {
+						foreign = append(foreign, name)
+					}
+				}
+
+				switch {
+				case len(g.Members) == 0:
+					continue
+				case len(foreign) > 0:
+					return nil, fmt.Errorf("group %s: %s not of type %s", g.Name, strings.Join(foreign, ", "), m.Type)
+				case !token.IsIdentifier(g.Func):
+					return nil, fmt.Errorf("group %s: not a valid name", g.Name)
+				case seen[g.Name]:
+					return nil, fmt.Errorf("group %s declared more than once", g.Name)
+				}
+				seen[g.Name] = true
+				gs = append(gs, g)
+			}
+		}
+	}
+	return gs, nil
+}
+
+// args reconstructs the command line, for the generated file's header.
+func args(opts Options) string {
+	a := "-type " + opts.Type
+	if opts.Set != "" {
+		a += " -set " + opts.Set
+	}
+	if opts.Dense {
+		a += " -dense"
+	}
+	return a
+}
+
+// exported returns prefix, capitalised if name is exported.
+func exported(name, prefix string) string {
+	if token.IsExported(name) {
+		return upperFirst(prefix)
+	}
+	return prefix
+}
+
+func upperFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+// camel converts a group name like more_bacon or more-bacon to MoreBacon.
+func camel(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' }) {
+		b.WriteString(upperFirst(part))
+	}
+	return b.String()
+}
+
+var tmpl = template.Must(template.New("set").Funcs(template.FuncMap{"join": strings.Join}).Parse(setTemplate))
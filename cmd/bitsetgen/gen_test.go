@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// write creates a package in a temporary directory, from a single file.
+func write(t *testing.T, src string) string {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "enum.go"), []byte(src), 0o644))
+	return dir
+}
+
+func TestGenerate_Burger(t *testing.T) {
+	dir := filepath.Join("..", "..", "example", "burger")
+	out := filepath.Join(dir, "topping_set.go")
+
+	want, err := os.ReadFile(out)
+	assert.NoError(t, err)
+
+	got, err := Generate(dir, Options{Type: "topping", Skip: out})
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "example/burger is out of date; run go generate")
+}
+
+func TestGenerate(t *testing.T) {
+	dir := write(t, `package colors
+
+import "image/color"
+
+type Color uint8
+
+const (
+	Red Color = iota + 1
+	Green
+	Blue
+	Crimson = Red
+
+	Other uint8 = 7
+)
+
+var _ = color.Black
+
+//bitset:group warm Red
+//bitset:group unrelated Other
+`)
+
+	src, err := Generate(dir, Options{Type: "Color"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), `"bitsetgen -type Color"`)
+	assert.Contains(t, string(src), "b fixed.Set64[Color]")
+	assert.Contains(t, string(src), "func NewColorSet(")
+	assert.Contains(t, string(src), "func ParseColorSet(")
+	assert.Contains(t, string(src), "return NewColorSet(Red, Green, Blue)")
+	assert.Contains(t, string(src), "func ColorSetWarm() ColorSet")
+	assert.NotContains(t, string(src), "Unrelated")
+	assert.NotContains(t, string(src), "Other")
+
+	// aliases parse, but don't print
+	assert.Contains(t, string(src), `case "Crimson":`)
+	assert.NotContains(t, string(src), "case Crimson:")
+
+	src, err = Generate(dir, Options{Type: "Color", Set: "palette", Dense: true})
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), `"bitsetgen -type Color -set palette -dense"`)
+	assert.Contains(t, string(src), "b *bits.Bitset[uint64, Color]")
+	assert.Contains(t, string(src), "func newPalette(")
+	assert.Contains(t, string(src), "func paletteWarm() palette")
+}
+
+func TestGenerate_Size(t *testing.T) {
+	for max, want := range map[string]string{"63": "Set64", "64": "Set128", "255": "Set256"} {
+		dir := write(t, "package p\n\ntype T uint\n\nconst A, B T = 0, "+max+"\n")
+		src, err := Generate(dir, Options{Type: "T"})
+		assert.NoError(t, err)
+		assert.Contains(t, string(src), "fixed."+want+"[T]")
+	}
+
+	dir := write(t, "package p\n\ntype T uint\n\nconst A, B T = 0, 256\n")
+	_, err := Generate(dir, Options{Type: "T"})
+	assert.ErrorContains(t, err, "use -dense")
+
+	_, err = Generate(dir, Options{Type: "T", Dense: true})
+	assert.NoError(t, err)
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	for name, tc := range map[string]struct{ src, err string }{
+		"missing":   {"package p\n", "type T not found"},
+		"signed":    {"package p\n\ntype T int\n\nconst A T = 1\n", "not a bitset.Value"},
+		"empty":     {"package p\n\ntype T uint\n", "no constants of type T"},
+		"malformed": {"package p\n\ntype T uint\n\nconst A T = 1\n\n//bitset:group a\n", "malformed directive"},
+		"mixed":     {"package p\n\ntype T uint\n\nconst A T = 1\n\n//bitset:group a A,B\n", "group a: B not of type T"},
+		"duplicate": {"package p\n\ntype T uint\n\nconst A T = 1\n\n//bitset:group a A\n//bitset:group a A\n", "more than once"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Generate(write(t, tc.src), Options{Type: "T"})
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Bitsetgen generates typed, allocation-free sets for enum-style constants.
+//
+// Given a type whose values are declared as constants, e.g.
+//
+//	type topping uint
+//
+//	const (
+//		LETTUCE topping = iota + 1
+//		TOMATO
+//		BACON
+//		MORE_BACON
+//	)
+//
+//	//bitset:group bacon BACON,MORE_BACON
+//
+// running `bitsetgen -type topping` in the package directory writes topping_set.go, containing a toppingSet type with
+// constant names in String, Parse, JSON encoding as a list of names, toppingSetAll and toppingSetNone universes,
+// and a toppingSetBacon group for every //bitset:group directive.
+//
+// The set is backed by a dense/fixed set sized to fit the largest constant, or by dense/bits when -dense is given.
+//
+// Typically it's invoked through go generate:
+//
+//	//go:generate go run github.com/zblach/go-bitset/cmd/bitsetgen -type topping
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeName = flag.String("type", "", "name of the constants' type; required")
+	setName  = flag.String("set", "", "name of the generated set type; defaults to <type>Set")
+	output   = flag.String("output", "", "output file name; defaults to <type>_set.go")
+	dense    = flag.Bool("dense", false, "back the set with dense/bits, instead of dense/fixed")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("bitsetgen: ")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: bitsetgen -type T [-set name] [-output file] [-dense] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeName == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+
+	out := *output
+	if out == "" {
+		out = strings.ToLower(*typeName) + "_set.go"
+	}
+	out = filepath.Join(dir, out)
+
+	src, err := Generate(dir, Options{Type: *typeName, Set: *setName, Dense: *dense, Skip: out})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+const setTemplate = `// Code generated by "bitsetgen {{.Args}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/zblach/go-bitset/dense/{{if .Dense}}bits{{else}}fixed{{end}}"
+)
+
+{{if .Dense -}}
+// {{.Set}} is a set of {{.Type}} values. The zero value is an empty set.
+// Sets are immutable; With, Without, Union and Intersect return new sets.
+type {{.Set}} struct {
+	b *bits.Bitset[uint64, {{.Type}}]
+}
+{{- else -}}
+// {{.Set}} is a set of {{.Type}} values. The zero value is an empty set.
+// It's a comparable value; With, Without, Union and Intersect return modified copies.
+type {{.Set}} struct {
+	b fixed.{{.Fixed}}[{{.Type}}]
+}
+{{- end}}
+
+// {{.New}} returns a set of the given values.
+func {{.New}}(vals ...{{.Type}}) {{.Set}} {
+	return {{.Set}}{}.With(vals...)
+}
+
+// {{.All}} returns the set of every {{.Type}} constant.
+func {{.All}}() {{.Set}} {
+	return {{.New}}({{range $i, $v := .Unique}}{{if $i}}, {{end}}{{$v.Name}}{{end}})
+}
+
+// {{.None}} returns the empty set.
+func {{.None}}() {{.Set}} {
+	return {{.Set}}{}
+}
+{{range .Groups}}
+// {{.Func}} returns the {{.Name}} group: {{join .Members ", "}}.
+func {{.Func}}() {{$.Set}} {
+	return {{$.New}}({{join .Members ", "}})
+}
+{{end}}
+// Has reports whether v is in the set.
+func (s {{.Set}}) Has(v {{.Type}}) bool {
+{{- if .Dense}}
+	return s.b != nil && s.b.Get(v)
+{{- else}}
+	return s.b.Get(v)
+{{- end}}
+}
+
+// With returns a copy of the set with the values added.
+func (s {{.Set}}) With(vals ...{{.Type}}) {{.Set}} {
+{{- if .Dense}}
+	b := bits.New[uint64, {{.Type}}](0)
+	if s.b != nil {
+		b = s.b.Copy()
+	}
+	b.Set(vals...)
+	return {{.Set}}{b}
+{{- else}}
+	return {{.Set}}{s.b.With(vals...)}
+{{- end}}
+}
+
+// Without returns a copy of the set with the values removed.
+func (s {{.Set}}) Without(vals ...{{.Type}}) {{.Set}} {
+{{- if .Dense}}
+	if s.b == nil {
+		return s
+	}
+	b := s.b.Copy()
+	b.Unset(vals...)
+	return {{.Set}}{b}
+{{- else}}
+	return {{.Set}}{s.b.Without(vals...)}
+{{- end}}
+}
+
+// Union returns the values in either set.
+func (s {{.Set}}) Union(o {{.Set}}) {{.Set}} {
+{{- if .Dense}}
+	switch {
+	case s.b == nil:
+		return o
+	case o.b == nil:
+		return s
+	}
+	return {{.Set}}{s.b.Or(o.b)}
+{{- else}}
+	return {{.Set}}{s.b.Union(o.b)}
+{{- end}}
+}
+
+// Intersect returns the values in both sets.
+func (s {{.Set}}) Intersect(o {{.Set}}) {{.Set}} {
+{{- if .Dense}}
+	if s.b == nil || o.b == nil {
+		return {{.Set}}{}
+	}
+	return {{.Set}}{s.b.And(o.b)}
+{{- else}}
+	return {{.Set}}{s.b.Intersect(o.b)}
+{{- end}}
+}
+
+// Overlaps reports whether the sets have any values in common.
+func (s {{.Set}}) Overlaps(o {{.Set}}) bool {
+	return s.Intersect(o).Len() > 0
+}
+
+// Contains reports whether every value of o is in the set.
+func (s {{.Set}}) Contains(o {{.Set}}) bool {
+	return s.Intersect(o).Len() == o.Len()
+}
+
+// Equal reports whether the sets have the same values.
+func (s {{.Set}}) Equal(o {{.Set}}) bool {
+{{- if .Dense}}
+	return s.Len() == o.Len() && s.Contains(o)
+{{- else}}
+	return s == o
+{{- end}}
+}
+
+// Len is the number of values in the set.
+func (s {{.Set}}) Len() int {
+{{- if .Dense}}
+	if s.b == nil {
+		return 0
+	}
+{{- end}}
+	return int(s.b.Pop())
+}
+
+// Values returns the values in the set, in ascending order.
+func (s {{.Set}}) Values() []{{.Type}} {
+{{- if .Dense}}
+	if s.b == nil {
+		return nil
+	}
+{{- end}}
+	vals := make([]{{.Type}}, 0, s.Len())
+	it, _ := s.b.Iterate()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+// String returns the names of the values in the set, e.g. "{ {{- (index .Unique 0).Name}}}".
+func (s {{.Set}}) String() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range s.Values() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if name, ok := _{{.Set}}Name(v); ok {
+			b.WriteString(name)
+		} else {
+			fmt.Fprintf(&b, "{{.Type}}(%d)", v)
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// {{.Parse}} parses a set of {{.Type}} names, as returned by String.
+// The braces are optional, and names may be separated by commas, pipes or spaces.
+func {{.Parse}}(str string) ({{.Set}}, error) {
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, "{") && strings.HasSuffix(str, "}") {
+		str = str[1 : len(str)-1]
+	}
+
+	var vals []{{.Type}}
+	for _, name := range strings.FieldsFunc(str, func(r rune) bool { return r == ',' || r == '|' || unicode.IsSpace(r) }) {
+		v, ok := _{{.Set}}Value(name)
+		if !ok {
+			return {{.Set}}{}, fmt.Errorf("{{.Set}}: unknown {{.Type}} %q", name)
+		}
+		vals = append(vals, v)
+	}
+	return {{.New}}(vals...), nil
+}
+
+// MarshalJSON encodes the set as a list of names.
+func (s {{.Set}}) MarshalJSON() ([]byte, error) {
+	vals := s.Values()
+	names := make([]string, len(vals))
+	for i, v := range vals {
+		name, ok := _{{.Set}}Name(v)
+		if !ok {
+			return nil, fmt.Errorf("{{.Set}}: {{.Type}}(%d) has no name", v)
+		}
+		names[i] = name
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes a list of names.
+func (s *{{.Set}}) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+
+	vals := make([]{{.Type}}, len(names))
+	for i, name := range names {
+		v, ok := _{{.Set}}Value(name)
+		if !ok {
+			return fmt.Errorf("{{.Set}}: unknown {{.Type}} %q", name)
+		}
+		vals[i] = v
+	}
+	*s = {{.New}}(vals...)
+	return nil
+}
+
+func _{{.Set}}Name(v {{.Type}}) (string, bool) {
+	switch v {
+{{- range .Unique}}
+	case {{.Name}}:
+		return "{{.Name}}", true
+{{- end}}
+	}
+	return "", false
+}
+
+func _{{.Set}}Value(name string) ({{.Type}}, bool) {
+	switch name {
+{{- range .Values}}
+	case "{{.Name}}":
+		return {{.Name}}, true
+{{- end}}
+	}
+	return 0, false
+}
+`
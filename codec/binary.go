@@ -0,0 +1,305 @@
+// Package codec serializes sets, independently of their backend.
+//
+// Every backend implements json.Marshaler, encoding.TextMarshaler, encoding.BinaryMarshaler, driver.Valuer and their
+// decoding counterparts by way of this package. Decoding accepts every encoding, so the encoding chosen when writing
+// a set, through JSON or SQL, doesn't need to be known when reading it back.
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// ErrMalformed is returned, wrapped with the details, when decoding invalid input.
+var ErrMalformed = errors.New("codec: malformed input")
+
+// The binary format is a fixed-size header, followed by either the set's words or its ranges.
+//
+//	offset  size  field
+//	0       4     magic, "BSET"
+//	4       1     version, 1
+//	5       1     kind; 0 for words, 1 for ranges
+//	6       1     flags; bit 0 is set when the header counts and payload are big-endian
+//	7       1     reserved, 0
+//	8       8     population
+//	16      8     count, of words or ranges
+//	24            payload; count 64-bit words, where bit i of word w is member 64w+i,
+//	              or count pairs of 64-bit inclusive range bounds, ascending and non-adjacent
+const (
+	Magic      = "BSET"
+	Version    = 1
+	HeaderSize = 24
+)
+
+// Kind is the layout of an encoded set's payload.
+type Kind uint8
+
+const (
+	Words Kind = iota
+	Ranges
+)
+
+const flagBigEndian = 1 << 0
+
+// Header describes an encoded set.
+type Header struct {
+	Kind      Kind
+	BigEndian bool
+	Pop       uint64
+	Count     uint64 // number of words, or ranges
+}
+
+// order is the byte order of the counts and payload.
+func (h Header) order() binary.ByteOrder {
+	if h.BigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// Size is the length of the encoded set, including the header.
+func (h Header) Size() uint64 {
+	if h.Kind == Ranges {
+		return HeaderSize + h.Count*16
+	}
+	return HeaderSize + h.Count*8
+}
+
+// ParseHeader decodes and validates the header of an encoded set. The payload isn't examined.
+func ParseHeader(data []byte) (Header, error) {
+	if len(data) < HeaderSize || string(data[:4]) != Magic {
+		return Header{}, fmt.Errorf("%w: not an encoded set", ErrMalformed)
+	}
+	if data[4] != Version {
+		return Header{}, fmt.Errorf("%w: unsupported version %d", ErrMalformed, data[4])
+	}
+
+	h := Header{Kind: Kind(data[5]), BigEndian: data[6]&flagBigEndian != 0}
+	if h.Kind > Ranges {
+		return Header{}, fmt.Errorf("%w: unknown kind %d", ErrMalformed, h.Kind)
+	}
+	if data[6]&^flagBigEndian != 0 || data[7] != 0 {
+		return Header{}, fmt.Errorf("%w: unknown flags", ErrMalformed)
+	}
+
+	h.Pop = h.order().Uint64(data[8:])
+	h.Count = h.order().Uint64(data[16:])
+	if h.Count > uint64(len(data)) {
+		return Header{}, fmt.Errorf("%w: truncated", ErrMalformed)
+	}
+	return h, nil
+}
+
+// AppendHeader appends the encoded header to buf.
+func AppendHeader(buf []byte, h Header) []byte {
+	var flags byte
+	if h.BigEndian {
+		flags |= flagBigEndian
+	}
+	buf = append(buf, Magic...)
+	buf = append(buf, Version, byte(h.Kind), flags, 0)
+
+	var counts [16]byte
+	h.order().PutUint64(counts[:], h.Pop)
+	h.order().PutUint64(counts[8:], h.Count)
+	return append(buf, counts[:]...)
+}
+
+// run is an inclusive range of members.
+type run struct{ start, end uint64 }
+
+// runs collects the members of s as ranges, along with its population. Runnable sets are read a run at a time, and
+// others a member at a time.
+func runs[V bitset.Value](s iterable.Iterable[V]) (rs []run, pop uint64) {
+	if r, ok := s.(iterable.Runnable[V]); ok {
+		it := r.Runs()
+		for m, ok := it.Next(); ok; m, ok = it.Next() {
+			if m.Set {
+				rs = join(rs, uint64(m.Start), uint64(m.End))
+				pop += uint64(m.End-m.Start) + 1
+			}
+		}
+		return
+	}
+
+	it, _ := s.Iterate()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		rs = join(rs, uint64(v), uint64(v))
+		pop++
+	}
+	return
+}
+
+// join appends the run start-end to rs, merging it with the last run when they're adjacent.
+func join(rs []run, start, end uint64) []run {
+	if n := len(rs); n > 0 && rs[n-1].end+1 == start {
+		rs[n-1].end = end
+		return rs
+	}
+	return append(rs, run{start, end})
+}
+
+// MarshalBinary encodes s in the binary format, little-endian. Whichever of the two layouts is smaller is used.
+func MarshalBinary[V bitset.Value](s iterable.Iterable[V]) []byte {
+	rs, pop := runs(s)
+
+	var words uint64
+	if len(rs) > 0 {
+		words = rs[len(rs)-1].end/64 + 1
+	}
+
+	h := Header{Kind: Words, Pop: pop, Count: words}
+	if uint64(len(rs))*2 < words {
+		h = Header{Kind: Ranges, Pop: pop, Count: uint64(len(rs))}
+	}
+
+	buf := AppendHeader(make([]byte, 0, h.Size()), h)
+	if h.Kind == Ranges {
+		for _, r := range rs {
+			buf = binary.LittleEndian.AppendUint64(buf, r.start)
+			buf = binary.LittleEndian.AppendUint64(buf, r.end)
+		}
+		return buf
+	}
+
+	payload := make([]uint64, words)
+	for _, r := range rs {
+		for v := r.start; ; v++ {
+			payload[v/64] |= 1 << (v % 64)
+			if v == r.end {
+				break
+			}
+		}
+	}
+	for _, w := range payload {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	return buf
+}
+
+// UnmarshalBinary replaces the members of dst with the decoded set.
+// Input is validated in its entirety first, so dst is left unchanged on error.
+func UnmarshalBinary[V bitset.Value](dst bitset.Bitset[V], data []byte) error {
+	rs, err := decodeBinary[V](data)
+	if err != nil {
+		return err
+	}
+	return apply(dst, rs)
+}
+
+// decodeBinary validates an encoded set, and returns its members as ranges.
+func decodeBinary[V bitset.Value](data []byte) ([]run, error) {
+	h, err := ParseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) != h.Size() {
+		return nil, fmt.Errorf("%w: %d bytes, want %d", ErrMalformed, len(data), h.Size())
+	}
+
+	order, payload := h.order(), data[HeaderSize:]
+
+	var rs []run
+	if h.Kind == Ranges {
+		for i := uint64(0); i < h.Count; i++ {
+			r := run{order.Uint64(payload[i*16:]), order.Uint64(payload[i*16+8:])}
+			if len(rs) > 0 && rs[len(rs)-1].end == math.MaxUint64 {
+				return nil, fmt.Errorf("%w: range %d-%d follows the largest value", ErrMalformed, r.start, r.end)
+			}
+			if r.start > r.end || (len(rs) > 0 && r.start <= rs[len(rs)-1].end+1) {
+				return nil, fmt.Errorf("%w: range %d-%d is out of order", ErrMalformed, r.start, r.end)
+			}
+			rs = append(rs, r)
+		}
+	} else {
+		for i := uint64(0); i < h.Count; i++ {
+			w := order.Uint64(payload[i*8:])
+			for bit := uint64(0); w != 0; bit, w = bit+1, w>>1 {
+				if w&1 == 0 {
+					continue
+				}
+				if v := i*64 + bit; len(rs) > 0 && rs[len(rs)-1].end+1 == v {
+					rs[len(rs)-1].end = v
+				} else {
+					rs = append(rs, run{v, v})
+				}
+			}
+		}
+	}
+
+	var pop uint64
+	for _, r := range rs {
+		n := r.end - r.start + 1
+		if n == 0 || pop+n < pop {
+			return nil, fmt.Errorf("%w: population overflows", ErrMalformed)
+		}
+		pop += n
+	}
+	if pop != h.Pop {
+		return nil, fmt.Errorf("%w: population is %d, header says %d", ErrMalformed, pop, h.Pop)
+	}
+	if err := fit[V](rs); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// fit checks that every member can be represented by V. rs must be ascending.
+func fit[V bitset.Value](rs []run) error {
+	if len(rs) == 0 {
+		return nil
+	}
+	if max := rs[len(rs)-1].end; uint64(V(max)) != max {
+		return fmt.Errorf("%w: %d is out of range", ErrMalformed, max)
+	}
+	return nil
+}
+
+// ranger is implemented by backends which can set a range of values at once.
+type ranger[V bitset.Value] interface {
+	SetRange(lo, hi V)
+}
+
+// limiter is implemented by destinations which can only hold values below Limit, like the fixed-size sets.
+type limiter interface {
+	Limit() uint64
+}
+
+// apply replaces the members of dst with rs, a range at a time if dst can set them, and in batches otherwise.
+// rs must already fit V. Nothing is expanded, or changed, if it doesn't fit dst's limit.
+func apply[V bitset.Value](dst bitset.Bitset[V], rs []run) error {
+	if l, ok := dst.(limiter); ok && len(rs) > 0 {
+		if max := rs[len(rs)-1].end; max >= l.Limit() {
+			return fmt.Errorf("%w: %d is beyond the set's capacity of %d", ErrMalformed, max, l.Limit())
+		}
+	}
+
+	dst.Clear()
+	if r, ok := dst.(ranger[V]); ok {
+		for _, run := range rs {
+			r.SetRange(V(run.start), V(run.end))
+		}
+		return nil
+	}
+
+	batch := make([]V, 0, 1024)
+	for _, r := range rs {
+		for v := r.start; ; v++ {
+			batch = append(batch, V(v))
+			if len(batch) == cap(batch) {
+				dst.Set(batch...)
+				batch = batch[:0]
+			}
+			if v == r.end {
+				break
+			}
+		}
+	}
+	dst.Set(batch...)
+	return nil
+}
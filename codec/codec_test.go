@@ -0,0 +1,246 @@
+package codec_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/codec"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/fixed"
+	"github.com/zblach/go-bitset/internal/backends"
+	"github.com/zblach/go-bitset/iterable"
+	mapset "github.com/zblach/go-bitset/sparse/map"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+type set interface {
+	bitset.Bitset[uint16]
+	iterable.Iterable[uint16]
+
+	json.Marshaler
+	json.Unmarshaler
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	driver.Valuer
+	sql.Scanner
+}
+
+var members = []uint16{1, 3, 5, 6, 7, 8, 9, 200}
+
+func TestRoundTrip(t *testing.T) {
+	for name, mk := range backends.WithFixed[uint16]() {
+		s := mk().(set)
+		s.Set(members...)
+
+		data, err := s.MarshalJSON()
+		assert.NoError(t, err)
+		assert.Equal(t, "[1,3,5,6,7,8,9,200]", string(data), name)
+
+		text, err := s.MarshalText()
+		assert.NoError(t, err)
+		assert.Equal(t, "1,3,5-9,200", string(text), name)
+
+		bin, err := s.MarshalBinary()
+		assert.NoError(t, err)
+		val, err := s.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, bin, val, name)
+
+		ranges, err := json.Marshal(codec.JSON[uint16](s, codec.RangeList))
+		assert.NoError(t, err)
+		assert.Equal(t, `"1,3,5-9,200"`, string(ranges), name)
+
+		b64, err := json.Marshal(codec.JSON[uint16](s, codec.Base64))
+		assert.NoError(t, err)
+
+		bitString, err := codec.SQL[uint16](s, codec.BitVarying).Value()
+		assert.NoError(t, err)
+		assert.Len(t, bitString, 201, name)
+
+		for _, decode := range []func(set) error{
+			func(d set) error { return d.UnmarshalJSON(data) },
+			func(d set) error { return d.UnmarshalJSON(ranges) },
+			func(d set) error { return d.UnmarshalJSON(b64) },
+			func(d set) error { return d.UnmarshalText(text) },
+			func(d set) error { return d.UnmarshalBinary(bin) },
+			func(d set) error { return d.Scan(val) },
+			func(d set) error { return d.Scan(bitString) },
+			func(d set) error { return d.Scan([]byte(bitString.(string))) },
+		} {
+			d := mk().(set)
+			d.Set(4, 100) // replaced by the decoded members
+			assert.NoError(t, decode(d), name)
+			assert.Equal(t, members, iterable.Values[uint16](d), name)
+		}
+
+		assert.NoError(t, s.Scan(nil))
+		assert.Empty(t, iterable.Values[uint16](s), name)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	valid := codec.MarshalBinary[uint](bitsOf(1, 2, 3))
+
+	truncated := valid[:len(valid)-1]
+	badPop := append([]byte(nil), valid...)
+	badPop[8]++
+	badVersion := append([]byte(nil), valid...)
+	badVersion[4] = 2
+
+	for name, mk := range backends.WithFixed[uint16]() {
+		for _, decode := range []func(set) error{
+			func(d set) error { return d.UnmarshalJSON([]byte(`{"a":1}`)) },
+			func(d set) error { return d.UnmarshalJSON([]byte(`[1,-2]`)) },
+			func(d set) error { return d.UnmarshalJSON([]byte(`[1,65536]`)) },
+			func(d set) error { return d.UnmarshalJSON([]byte(`"3-1"`)) },
+			func(d set) error { return d.UnmarshalJSON([]byte(`"1,,2"`)) },
+			func(d set) error { return d.UnmarshalJSON([]byte(`"!!!"`)) },
+			func(d set) error { return d.UnmarshalJSON([]byte(`"QlNFVA"`)) },
+			func(d set) error { return d.UnmarshalText([]byte("1-x")) },
+			func(d set) error { return d.UnmarshalBinary(nil) },
+			func(d set) error { return d.UnmarshalBinary(truncated) },
+			func(d set) error { return d.UnmarshalBinary(badPop) },
+			func(d set) error { return d.UnmarshalBinary(badVersion) },
+			func(d set) error { return d.Scan("10201") },
+			func(d set) error { return d.Scan(42) },
+		} {
+			d := mk().(set)
+			d.Set(4)
+			assert.ErrorIs(t, decode(d), codec.ErrMalformed, name)
+			assert.Equal(t, []uint16{4}, iterable.Values[uint16](d), "%s: unchanged on error", name)
+		}
+	}
+
+	// beyond a fixed set's capacity, which is smaller than V
+	var f fixed.Set64[uint16]
+	assert.ErrorIs(t, f.UnmarshalText([]byte("1,64")), codec.ErrMalformed)
+	assert.Zero(t, f.Pop())
+}
+
+func TestWideRanges(t *testing.T) {
+	// ranges are set whole, or rejected against the capacity, rather than expanded a value at a time
+	wide := rangeset.New[uint]()
+	assert.NoError(t, wide.UnmarshalText([]byte("0-4294967295")))
+	assert.Equal(t, uint(1<<32), wide.Pop())
+
+	bin := codec.AppendHeader(nil, codec.Header{Kind: codec.Ranges, Pop: 1 << 32, Count: 1})
+	bin = binary.LittleEndian.AppendUint64(bin, 0)
+	bin = binary.LittleEndian.AppendUint64(bin, 1<<32-1)
+	assert.NoError(t, wide.UnmarshalBinary(bin))
+	assert.Equal(t, uint(1<<32), wide.Pop())
+
+	var f fixed.Set64[uint]
+	f.Set(4)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"0-18446744073709551615"`), &f), codec.ErrMalformed)
+	assert.ErrorIs(t, f.UnmarshalBinary(bin), codec.ErrMalformed)
+	assert.ErrorIs(t, f.Scan(bin), codec.ErrMalformed)
+	assert.Equal(t, []uint{4}, iterable.Values[uint](&f), "unchanged on error")
+
+	assert.NoError(t, f.UnmarshalText([]byte("2-63")))
+	assert.Equal(t, uint(62), f.Pop())
+}
+
+func TestBinary(t *testing.T) {
+	// dense members are encoded as words
+	dense := codec.MarshalBinary[uint](bitsOf(0, 2, 64))
+	h, err := codec.ParseHeader(dense)
+	assert.NoError(t, err)
+	assert.Equal(t, codec.Header{Kind: codec.Words, Pop: 3, Count: 2}, h)
+	assert.Equal(t, uint64(len(dense)), h.Size())
+	assert.Equal(t, uint64(0b101), binary.LittleEndian.Uint64(dense[codec.HeaderSize:]))
+
+	// sparse ones as ranges
+	sparse := codec.MarshalBinary[uint](bitsOf(10, 11, 12, 5000))
+	h, err = codec.ParseHeader(sparse)
+	assert.NoError(t, err)
+	assert.Equal(t, codec.Header{Kind: codec.Ranges, Pop: 4, Count: 2}, h)
+
+	// big-endian input is accepted
+	be := codec.AppendHeader(nil, codec.Header{Kind: codec.Ranges, BigEndian: true, Pop: 3, Count: 1})
+	be = binary.BigEndian.AppendUint64(be, 7)
+	be = binary.BigEndian.AppendUint64(be, 9)
+	s := bits.New[uint64, uint](0)
+	assert.NoError(t, s.UnmarshalBinary(be))
+	assert.Equal(t, []uint{7, 8, 9}, iterable.Values[uint](s))
+
+	// overlapping ranges are rejected
+	bad := codec.AppendHeader(nil, codec.Header{Kind: codec.Ranges, Pop: 4, Count: 2})
+	for _, v := range []uint64{1, 2, 2, 3} {
+		bad = binary.LittleEndian.AppendUint64(bad, v)
+	}
+	assert.ErrorIs(t, s.UnmarshalBinary(bad), codec.ErrMalformed)
+
+	ranges := func(pop uint64, bounds ...uint64) []byte {
+		b := codec.AppendHeader(nil, codec.Header{Kind: codec.Ranges, Pop: pop, Count: uint64(len(bounds) / 2)})
+		for _, v := range bounds {
+			b = binary.LittleEndian.AppendUint64(b, v)
+		}
+		return b
+	}
+	wide := rangeset.New[uint64]()
+	wide.Set(4)
+	// nothing can follow a range ending at the largest value, where the order check would wrap
+	assert.ErrorIs(t, wide.UnmarshalBinary(ranges(math.MaxUint64-4+2, 5, math.MaxUint64, 1, 2)), codec.ErrMalformed)
+	// every value, which the population can't count
+	assert.ErrorIs(t, wide.UnmarshalBinary(ranges(0, 0, math.MaxUint64)), codec.ErrMalformed)
+	assert.Equal(t, []uint64{4}, iterable.Values[uint64](wide), "unchanged on error")
+
+	// the base64 encoding is just that
+	b64, _ := codec.MarshalJSON[uint](bitsOf(1), codec.Base64)
+	assert.Equal(t, `"`+base64.StdEncoding.EncodeToString(codec.MarshalBinary[uint](bitsOf(1)))+`"`, string(b64))
+}
+
+func TestStructField(t *testing.T) {
+	type config struct {
+		Ports *bits.Bitset[uint64, uint16] `json:"ports"`
+		Flags rangeset.Bitset[uint16]      `json:"flags"`
+		Small fixed.Set64[uint16]          `json:"small"`
+		Never *mapset.Bitset[uint16]       `json:"never"`
+	}
+
+	var c config
+	assert.NoError(t, json.Unmarshal([]byte(`{"ports": "80,443,8000-8002", "flags": [2, 1], "small": "5", "never": null}`), &c))
+	assert.Equal(t, []uint16{80, 443, 8000, 8001, 8002}, iterable.Values[uint16](c.Ports))
+	assert.Equal(t, []uint16{1, 2}, iterable.Values[uint16](&c.Flags))
+	assert.True(t, c.Small.Get(5))
+	assert.Nil(t, c.Never)
+
+	data, err := json.Marshal(&c)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"ports": [80,443,8000,8001,8002], "flags": [1,2], "small": [5], "never": null}`, string(data))
+}
+
+func bitsOf(vals ...uint) *bits.Bitset[uint64, uint] {
+	s := bits.New[uint64, uint](0)
+	s.Set(vals...)
+	return s
+}
+
+func TestRunnable(t *testing.T) {
+	// encoded from the ranges, rather than member by member
+	s := rangeset.New[uint64]()
+	s.SetRange(10, math.MaxUint64)
+
+	bin := codec.MarshalBinary[uint64](s)
+	h, err := codec.ParseHeader(bin)
+	assert.NoError(t, err)
+	assert.Equal(t, codec.Header{Kind: codec.Ranges, Pop: math.MaxUint64 - 9, Count: 1}, h)
+	assert.Equal(t, "10-18446744073709551615", string(codec.MarshalText[uint64](s)))
+	j, err := codec.MarshalJSON[uint64](s, codec.RangeList)
+	assert.NoError(t, err)
+	assert.Equal(t, `"10-18446744073709551615"`, string(j))
+
+	back := rangeset.New[uint64]()
+	assert.NoError(t, back.UnmarshalBinary(bin))
+	assert.Equal(t, s.Pop(), back.Pop())
+}
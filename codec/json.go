@@ -0,0 +1,196 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// JSONEncoding selects how a set is written as JSON.
+type JSONEncoding int
+
+const (
+	// Members is an array of every member: [1,3,5,6,7]
+	Members JSONEncoding = iota
+	// RangeList is a string of members and inclusive ranges, the same as the text encoding: "1,3,5-7"
+	RangeList
+	// Base64 is a string holding the standard base64 encoding of the binary format.
+	Base64
+)
+
+// JSON wraps s, so that it's marshalled with the given encoding: json.Marshal(codec.JSON[uint](s, codec.RangeList))
+func JSON[V bitset.Value](s iterable.Iterable[V], enc JSONEncoding) json.Marshaler {
+	return jsonSet[V]{s, enc}
+}
+
+type jsonSet[V bitset.Value] struct {
+	s   iterable.Iterable[V]
+	enc JSONEncoding
+}
+
+func (j jsonSet[V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(j.s, j.enc)
+}
+
+// MarshalJSON encodes s as JSON.
+func MarshalJSON[V bitset.Value](s iterable.Iterable[V], enc JSONEncoding) ([]byte, error) {
+	switch enc {
+	case Members:
+		buf := []byte{'['}
+		it, _ := s.Iterate()
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			if len(buf) > 1 {
+				buf = append(buf, ',')
+			}
+			buf = strconv.AppendUint(buf, uint64(v), 10)
+		}
+		return append(buf, ']'), nil
+	case RangeList:
+		text := MarshalText(s)
+		return json.Marshal(string(text))
+	case Base64:
+		return json.Marshal(base64.StdEncoding.EncodeToString(MarshalBinary(s)))
+	}
+	return nil, fmt.Errorf("codec: unknown JSON encoding %d", enc)
+}
+
+// UnmarshalJSON replaces the members of dst with the decoded set, which may be in any of the JSON encodings.
+// null leaves dst unchanged, as does an error.
+func UnmarshalJSON[V bitset.Value](dst bitset.Bitset[V], data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	var rs []run
+	switch {
+	case string(data) == "null":
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var members []uint64
+		if err := json.Unmarshal(data, &members); err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		rs = fromMembers(members)
+	case len(data) > 0 && data[0] == '"':
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+
+		var err error
+		if str == "" || (str[0] >= '0' && str[0] <= '9') {
+			rs, err = parseRanges(str)
+		} else {
+			var raw []byte
+			if raw, err = base64.StdEncoding.DecodeString(str); err != nil {
+				return fmt.Errorf("%w: %v", ErrMalformed, err)
+			}
+			rs, err = decodeBinary[V](raw)
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: want an array or a string", ErrMalformed)
+	}
+
+	if err := fit[V](rs); err != nil {
+		return err
+	}
+	return apply(dst, rs)
+}
+
+// MarshalText encodes s as a list of members and inclusive ranges, e.g. 1,3,5-9.
+// Like the formatter, only runs of three or more members are written as ranges.
+func MarshalText[V bitset.Value](s iterable.Iterable[V]) []byte {
+	rs, _ := runs(s)
+
+	var buf []byte
+	for _, r := range rs {
+		if len(buf) > 0 {
+			buf = append(buf, ',')
+		}
+		switch r.end - r.start {
+		case 0:
+			buf = strconv.AppendUint(buf, r.start, 10)
+		case 1:
+			buf = strconv.AppendUint(buf, r.start, 10)
+			buf = append(buf, ',')
+			buf = strconv.AppendUint(buf, r.end, 10)
+		default:
+			buf = strconv.AppendUint(buf, r.start, 10)
+			buf = append(buf, '-')
+			buf = strconv.AppendUint(buf, r.end, 10)
+		}
+	}
+	return buf
+}
+
+// UnmarshalText replaces the members of dst with the decoded list of members and ranges.
+// Spaces are allowed around the members, which needn't be in order. dst is left unchanged on error.
+func UnmarshalText[V bitset.Value](dst bitset.Bitset[V], text []byte) error {
+	rs, err := parseRanges(string(text))
+	if err != nil {
+		return err
+	}
+	if err := fit[V](rs); err != nil {
+		return err
+	}
+	return apply(dst, rs)
+}
+
+// parseRanges parses a list of members and ranges, returning them sorted and merged.
+func parseRanges(str string) ([]run, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+
+	var rs []run
+	for _, item := range strings.Split(str, ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(item), "-")
+
+		start, err := strconv.ParseUint(strings.TrimSpace(lo), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a member or range", ErrMalformed, item)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.ParseUint(strings.TrimSpace(hi), 10, 64); err != nil || end < start {
+				return nil, fmt.Errorf("%w: %q is not a member or range", ErrMalformed, item)
+			}
+		}
+		rs = append(rs, run{start, end})
+	}
+	return merge(rs), nil
+}
+
+// fromMembers converts a list of members, in any order, to ranges.
+func fromMembers(members []uint64) []run {
+	rs := make([]run, len(members))
+	for i, v := range members {
+		rs[i] = run{v, v}
+	}
+	return merge(rs)
+}
+
+// merge sorts ranges, and combines those which overlap or touch.
+func merge(rs []run) []run {
+	sort.Slice(rs, func(i, j int) bool { return rs[i].start < rs[j].start })
+
+	out := rs[:0]
+	for _, r := range rs {
+		if n := len(out); n > 0 && (r.start <= out[n-1].end || r.start-1 == out[n-1].end) {
+			if r.end > out[n-1].end {
+				out[n-1].end = r.end
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package codec
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// SQLEncoding selects how a set is stored in a database column.
+type SQLEncoding int
+
+const (
+	// Bytea stores the binary format, for bytea or blob columns.
+	Bytea SQLEncoding = iota
+	// BitVarying stores a string of 0s and 1s, where character i is 1 for member i, for Postgres bit varying columns.
+	BitVarying
+)
+
+// SQL wraps s, so that it's stored with the given encoding: db.Exec(query, codec.SQL[uint](s, codec.BitVarying))
+func SQL[V bitset.Value](s iterable.Iterable[V], enc SQLEncoding) driver.Valuer {
+	return sqlSet[V]{s, enc}
+}
+
+type sqlSet[V bitset.Value] struct {
+	s   iterable.Iterable[V]
+	enc SQLEncoding
+}
+
+func (q sqlSet[V]) Value() (driver.Value, error) {
+	return Value(q.s, q.enc)
+}
+
+// Value encodes s for a database column.
+func Value[V bitset.Value](s iterable.Iterable[V], enc SQLEncoding) (driver.Value, error) {
+	switch enc {
+	case Bytea:
+		return MarshalBinary(s), nil
+	case BitVarying:
+		var b strings.Builder
+		it, _ := s.Iterate()
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			for uint64(b.Len()) < uint64(v) {
+				b.WriteByte('0')
+			}
+			b.WriteByte('1')
+		}
+		return b.String(), nil
+	}
+	return nil, fmt.Errorf("codec: unknown SQL encoding %d", enc)
+}
+
+// Scan replaces the members of dst with a value read from a database column, in either of the SQL encodings.
+// NULL clears dst. dst is left unchanged on error.
+func Scan[V bitset.Value](dst bitset.Bitset[V], src any) error {
+	var rs []run
+	var err error
+
+	switch src := src.(type) {
+	case nil:
+		dst.Clear()
+		return nil
+	case []byte:
+		if bytes.HasPrefix(src, []byte(Magic)) {
+			rs, err = decodeBinary[V](src)
+		} else {
+			rs, err = parseBits(string(src))
+		}
+	case string:
+		rs, err = parseBits(src)
+	default:
+		return fmt.Errorf("%w: can't scan %T", ErrMalformed, src)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fit[V](rs); err != nil {
+		return err
+	}
+	return apply(dst, rs)
+}
+
+// parseBits parses a bit varying string.
+func parseBits(str string) ([]run, error) {
+	var rs []run
+	for i := 0; i < len(str); i++ {
+		switch v := uint64(i); str[i] {
+		case '0':
+		case '1':
+			if n := len(rs); n > 0 && rs[n-1].end+1 == v {
+				rs[n-1].end = v
+			} else {
+				rs = append(rs, run{v, v})
+			}
+		default:
+			return nil, fmt.Errorf("%w: %q is not a bit string", ErrMalformed, str)
+		}
+	}
+	return rs, nil
+}
@@ -0,0 +1,81 @@
+// Package convert moves members between backends, without expanding them into a slice of values.
+package convert
+
+import (
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/bools"
+	"github.com/zblach/go-bitset/iterable"
+	mapset "github.com/zblach/go-bitset/sparse/map"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+// ranger is implemented by backends which can set a range of values at once.
+type ranger[V bitset.Value] interface {
+	SetRange(lo, hi V)
+}
+
+// runner is implemented by backends which can enumerate their runs.
+type runner[V bitset.Value] interface {
+	Runs() iterable.Iter[iterable.Run[V]]
+}
+
+// Convert replaces the members of dst with those of src.
+//
+// When src enumerates its runs and dst can set ranges, members are copied a run at a time. That's the direct path
+// between bits, which finds runs and fills ranges a word at a time, and rangeset. Otherwise, members are streamed
+// into dst in batches by iterable.Copy.
+func Convert[V bitset.Value](dst bitset.Bitset[V], src iterable.Iterable[V]) {
+	r, isRanger := dst.(ranger[V])
+	runs, isRunner := src.(runner[V])
+	if !isRanger || !isRunner {
+		iterable.Copy(dst, src)
+		return
+	}
+
+	dst.Clear()
+	it := runs.Runs()
+	for run, ok := it.Next(); ok; run, ok = it.Next() {
+		if run.Set {
+			r.SetRange(run.Start, run.End)
+		}
+	}
+}
+
+// ToDense returns a copy of src as a bits.Bitset. A bools.Bitset is packed directly into words.
+func ToDense[W bits.Width, V bitset.Value](src iterable.Iterable[V]) *bits.Bitset[W, V] {
+	if b, ok := src.(*bools.Bitset[V]); ok {
+		var dst *bits.Bitset[W, V]
+		b.View(func(b []bool) { dst = bits.FromBools[W, V](b) })
+		return dst
+	}
+
+	dst := bits.New[W, V](0)
+	Convert[V](dst, src)
+	return dst
+}
+
+// ToBools returns a copy of src as a bools.Bitset. A bits.Bitset is unpacked directly from its words.
+func ToBools[V bitset.Value](src iterable.Iterable[V]) *bools.Bitset[V] {
+	if b, ok := src.(interface{ Bools() []bool }); ok {
+		return bools.FromSlice[V](b.Bools())
+	}
+
+	dst := bools.New[V](0)
+	Convert[V](dst, src)
+	return dst
+}
+
+// ToRanges returns a copy of src as a rangeset.Bitset.
+func ToRanges[V bitset.Value](src iterable.Iterable[V]) *rangeset.Bitset[V] {
+	dst := rangeset.New[V]()
+	Convert[V](dst, src)
+	return dst
+}
+
+// ToMap returns a copy of src as a mapset.Bitset.
+func ToMap[V bitset.Value](src iterable.Iterable[V]) *mapset.Bitset[V] {
+	dst := mapset.New[V]()
+	Convert[V](dst, src)
+	return dst
+}
@@ -0,0 +1,71 @@
+package convert
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/bools"
+	"github.com/zblach/go-bitset/internal/backends"
+	"github.com/zblach/go-bitset/iterable"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+func TestConvert(t *testing.T) {
+	members := []uint16{0, 1, 2, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 65535}
+
+	for from, mkSrc := range backends.All[uint16]() {
+		src := mkSrc()
+		src.Set(members...)
+
+		for to, mkDst := range backends.All[uint16]() {
+			dst := mkDst()
+			dst.Set(3, 4, 5) // replaced
+			Convert[uint16](dst, src)
+			assert.Equal(t, members, iterable.Values[uint16](dst), "%s -> %s", from, to)
+		}
+
+		assert.Equal(t, members, iterable.Values[uint16](ToDense[uint32, uint16](src)), from)
+		assert.Equal(t, members, iterable.Values[uint16](ToBools[uint16](src)), from)
+		assert.Equal(t, members, iterable.Values[uint16](ToRanges[uint16](src)), from)
+		assert.Equal(t, members, iterable.Values[uint16](ToMap[uint16](src)), from)
+	}
+}
+
+func TestConvert_Pop(t *testing.T) {
+	src := bools.New[uint](0)
+	src.Set(1, 5, 64, 65, 66)
+
+	dense := ToDense[uint64, uint](src)
+	assert.Equal(t, uint(5), dense.Pop())
+	assert.Equal(t, uint(5), ToBools[uint](dense).Pop())
+	assert.Equal(t, uint(5), ToRanges[uint](dense).Pop())
+}
+
+// allocated returns the bytes allocated by fn.
+func allocated(fn func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func TestConvert_NoExpansion(t *testing.T) {
+	const n = 1 << 24 // 128 MiB, as a []uint64
+
+	ranges := rangeset.New[uint64]()
+	ranges.SetRange(0, n-1)
+
+	var dense *bits.Bitset[uint64, uint64]
+	bytes := allocated(func() { dense = ToDense[uint64, uint64](ranges) })
+	assert.Equal(t, uint(n), dense.Pop())
+	assert.Less(t, bytes, uint64(n/8*3), "only the words should be allocated")
+
+	var back *rangeset.Bitset[uint64]
+	bytes = allocated(func() { back = ToRanges[uint64](dense) })
+	assert.Equal(t, uint(n), back.Pop())
+	assert.Less(t, bytes, uint64(n/8*2), "only the run iterator's copy of the words should be allocated")
+}
@@ -0,0 +1,140 @@
+package bitset
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+)
+
+// Pager lists the members of a set one page at a time.
+//
+// Page takes a Cursor rather than the last value seen, so that the next page can tell whether the set changed in between.
+// Callers which only hold a value can resume with After(v), or use PageAfter.
+type Pager[V Value] interface {
+	// Page returns up to limit members which follow the cursor, in ascending order, and the cursor for the next page.
+	// done is true when there are no members after this page. A limit of zero or less returns all remaining members.
+	Page(after Cursor[V], limit int) (vals []V, next Cursor[V], done bool)
+}
+
+// PageAfter returns up to limit members of p which are greater than after, like p.Page(After(after), limit).
+func PageAfter[V Value](p Pager[V], after V, limit int) (vals []V, next Cursor[V], done bool) {
+	return p.Page(After(after), limit)
+}
+
+// Cursor is an opaque, serializable position in a paginated listing.
+// The zero Cursor starts at the beginning of a set.
+//
+// Pagination always continues after the last value that was returned, even if the set has been modified since.
+// Members added or removed behind the cursor are not revisited. Changed reports whether this happened.
+type Cursor[V Value] struct {
+	after   V
+	version uint64
+
+	started   bool // after is valid
+	versioned bool // version is valid
+	changed   bool
+}
+
+const (
+	cursorStarted byte = 1 << iota
+	cursorVersioned
+	cursorChanged
+)
+
+// ErrInvalidCursor is returned when decoding a malformed cursor.
+var ErrInvalidCursor = errors.New("bitset: invalid cursor")
+
+// After is a cursor which resumes after a known value. It carries no version, so it never reports changes.
+func After[V Value](v V) Cursor[V] {
+	return Cursor[V]{after: v, started: true}
+}
+
+// Start returns the smallest value a page following this cursor may contain.
+// ok is false if the cursor is already at the end of the range of V.
+func (c Cursor[V]) Start() (from V, ok bool) {
+	if !c.started {
+		return 0, true
+	}
+	from = c.after + 1
+	return from, from > c.after
+}
+
+// Advance builds the cursor following a page of vals, read from a set at the given version. It's used by Pager implementations.
+func (c Cursor[V]) Advance(vals []V, version uint64) Cursor[V] {
+	next := Cursor[V]{
+		after:     c.after,
+		version:   version,
+		started:   c.started,
+		versioned: true,
+		changed:   c.versioned && c.version != version,
+	}
+	if len(vals) > 0 {
+		next.after, next.started = vals[len(vals)-1], true
+	}
+	return next
+}
+
+// Changed reports whether the set was modified between the page which issued the previous cursor, and the page which issued this one.
+func (c Cursor[V]) Changed() bool {
+	return c.changed
+}
+
+// MarshalText implements encoding.TextMarshaler. Cursors are encoded as URL-safe base64.
+func (c Cursor[V]) MarshalText() ([]byte, error) {
+	buf := make([]byte, 1, 1+2*binary.MaxVarintLen64)
+
+	if c.started {
+		buf[0] |= cursorStarted
+	}
+	if c.versioned {
+		buf[0] |= cursorVersioned
+	}
+	if c.changed {
+		buf[0] |= cursorChanged
+	}
+	buf = binary.AppendUvarint(buf, uint64(c.after))
+	buf = binary.AppendUvarint(buf, c.version)
+
+	text := make([]byte, base64.RawURLEncoding.EncodedLen(len(buf)))
+	base64.RawURLEncoding.Encode(text, buf)
+	return text, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Cursor[V]) UnmarshalText(text []byte) error {
+	buf := make([]byte, base64.RawURLEncoding.DecodedLen(len(text)))
+	if _, err := base64.RawURLEncoding.Decode(buf, text); err != nil || len(buf) == 0 {
+		return ErrInvalidCursor
+	}
+
+	flags := buf[0]
+	after, n := binary.Uvarint(buf[1:])
+	if n <= 0 || uint64(V(after)) != after {
+		return ErrInvalidCursor
+	}
+	version, m := binary.Uvarint(buf[1+n:])
+	if m <= 0 || 1+n+m != len(buf) || flags&^(cursorStarted|cursorVersioned|cursorChanged) != 0 {
+		return ErrInvalidCursor
+	}
+
+	*c = Cursor[V]{
+		after:     V(after),
+		version:   version,
+		started:   flags&cursorStarted != 0,
+		versioned: flags&cursorVersioned != 0,
+		changed:   flags&cursorChanged != 0,
+	}
+	return nil
+}
+
+// String returns the encoded cursor.
+func (c Cursor[V]) String() string {
+	text, _ := c.MarshalText()
+	return string(text)
+}
+
+// ParseCursor decodes a cursor produced by String or MarshalText.
+func ParseCursor[V Value](s string) (c Cursor[V], err error) {
+	err = c.UnmarshalText([]byte(s))
+	return
+}
@@ -0,0 +1,46 @@
+package bitset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	var zero Cursor[uint]
+	c := zero.Advance([]uint{3, 9, 1_000_000}, 42)
+
+	parsed, err := ParseCursor[uint](c.String())
+	assert.NoError(t, err)
+	assert.Equal(t, c, parsed)
+
+	from, ok := parsed.Start()
+	assert.True(t, ok)
+	assert.Equal(t, uint(1_000_001), from)
+
+	changed := parsed.Advance(nil, 43)
+	assert.True(t, changed.Changed())
+	assert.False(t, changed.Advance(nil, 43).Changed())
+	assert.False(t, After[uint](7).Advance(nil, 43).Changed())
+}
+
+func TestCursor_Bounds(t *testing.T) {
+	from, ok := Cursor[uint8]{}.Start()
+	assert.True(t, ok)
+	assert.Equal(t, uint8(0), from)
+
+	_, ok = After[uint8](255).Start()
+	assert.False(t, ok)
+}
+
+func TestCursor_Malformed(t *testing.T) {
+	for _, s := range []string{"", "!!!", "AQ", "CICAgICAgICAgAE", "_wAA"} {
+		_, err := ParseCursor[uint](s)
+		assert.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+
+	// too large for uint8
+	c, _ := ParseCursor[uint16](After[uint16](300).String())
+	_, err := ParseCursor[uint8](c.String())
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -7,6 +7,8 @@ import (
 	mb "math/bits"
 
 	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/cow"
+	"github.com/zblach/go-bitset/internal/wait"
 	"github.com/zblach/go-bitset/mixin/logical"
 )
 
@@ -43,8 +45,12 @@ type Bitset[W Width, V bitset.Value] struct {
 
 	lock *sync.RWMutex
 
-	bits []W
-	pop  uint
+	bits    []W
+	pop     uint
+	version uint64      // incremented on every modification
+	shrink  float64     // AutoShrink threshold, or 0
+	log     cow.Log[W]  // original words, for snapshots
+	waiters wait.Signal // woken as members are added
 }
 
 // New instantiates a new bitset with an initial size of size.
@@ -71,8 +77,10 @@ func (s *Bitset[W, V]) Clear() {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	s.log.Freeze(s.bits)
 	s.bits = make([]W, 0)
 	s.pop = 0
+	s.version++
 }
 
 // Copy returns a deep copy of the bitset.
@@ -81,9 +89,10 @@ func (s *Bitset[W, V]) Copy() *Bitset[W, V] {
 	defer s.lock.RUnlock()
 
 	clone := &Bitset[W, V]{
-		lock: &sync.RWMutex{},
-		pop:  s.pop,
-		bits: make([]W, len(s.bits)),
+		lock:   &sync.RWMutex{},
+		pop:    s.pop,
+		bits:   make([]W, len(s.bits)),
+		shrink: s.shrink,
 	}
 	copy(clone.bits, s.bits)
 
@@ -122,13 +131,19 @@ func (s *Bitset[W, V]) Set(indices ...V) {
 
 	s.growright(uint(maxIndex))
 
+	pop := s.pop
 	for _, index := range indices {
 		elem, bit := indexToTuple[W](uint(index))
 		if (s.bits[elem] & bit) == 0 {
+			s.log.Touch(s.bits, int(elem), int(elem))
 			s.bits[elem] |= bit
 			s.pop += 1
 		}
 	}
+	if s.pop != pop {
+		s.version++
+		s.waiters.Notify()
+	}
 }
 
 // Unset one or more values in the bitset.
@@ -141,6 +156,7 @@ func (s *Bitset[W, V]) Unset(indices ...V) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	pop := s.pop
 	for _, index := range indices {
 		elem, bit := indexToTuple[W](uint(index))
 		if elem >= uint(len(s.bits)) {
@@ -148,10 +164,15 @@ func (s *Bitset[W, V]) Unset(indices ...V) {
 		}
 
 		if (s.bits[elem] & bit) != 0 {
+			s.log.Touch(s.bits, int(elem), int(elem))
 			s.bits[elem] &= ^bit
 			s.pop -= 1
 		}
 	}
+	if s.pop != pop {
+		s.autoshrink()
+		s.version++
+	}
 }
 
 // indexToTuple is a utility function to compute the memory element and bitmask, based on index.
@@ -161,6 +182,81 @@ func indexToTuple[W Width](index uint) (elem uint, bit W) {
 	return index / wbits, 1 << (index & (wbits - 1)) // equiv to index % wbits
 }
 
+// wordBits is the number of bits in each storage element.
+func wordBits[W Width]() uint {
+	return uint(unsafe.Sizeof(W(0)) << 3)
+}
+
+// nextSet finds the first set bit at or after from, skipping over empty words.
+func (s *Bitset[W, V]) nextSet(from uint) (uint, bool) {
+	wbits := wordBits[W]()
+	elem := from / wbits
+	if elem >= uint(len(s.bits)) {
+		return 0, false
+	}
+
+	if word := uint64(s.bits[elem]) >> (from % wbits); word != 0 {
+		return from + uint(mb.TrailingZeros64(word)), true
+	}
+	for elem++; elem < uint(len(s.bits)); elem++ {
+		if s.bits[elem] != 0 {
+			return elem*wbits + uint(mb.TrailingZeros64(uint64(s.bits[elem]))), true
+		}
+	}
+	return 0, false
+}
+
+// nextClear finds the first unset bit at or after from. Everything past the end of storage is unset.
+func (s *Bitset[W, V]) nextClear(from uint) uint {
+	wbits := wordBits[W]()
+	elem := from / wbits
+	if elem >= uint(len(s.bits)) {
+		return from
+	}
+
+	if word := uint64(^s.bits[elem]) >> (from % wbits); word != 0 {
+		return from + uint(mb.TrailingZeros64(word))
+	}
+	for elem++; elem < uint(len(s.bits)); elem++ {
+		if ^s.bits[elem] != 0 {
+			return elem*wbits + uint(mb.TrailingZeros64(uint64(^s.bits[elem])))
+		}
+	}
+	return uint(len(s.bits)) * wbits
+}
+
+// get is an unlocked Get.
+func (s *Bitset[W, V]) get(index uint) bool {
+	elem, bit := indexToTuple[W](index)
+	return elem < uint(len(s.bits)) && (s.bits[elem]&bit) != 0
+}
+
+// prevSet finds the last set bit at or before from, skipping over empty words.
+func (s *Bitset[W, V]) prevSet(from uint) (uint, bool) {
+	if len(s.bits) == 0 {
+		return 0, false
+	}
+
+	wbits := wordBits[W]()
+	elem := from / wbits
+	if elem >= uint(len(s.bits)) {
+		elem = uint(len(s.bits)) - 1
+		from = elem*wbits + wbits - 1
+	}
+
+	mask := uint64(1)<<(from%wbits+1) - 1 // bits at or below from. shifting by 64 yields 0, so this saturates.
+	if word := uint64(s.bits[elem]) & mask; word != 0 {
+		return elem*wbits + 63 - uint(mb.LeadingZeros64(word)), true
+	}
+	for elem > 0 {
+		elem--
+		if s.bits[elem] != 0 {
+			return elem*wbits + 63 - uint(mb.LeadingZeros64(uint64(s.bits[elem]))), true
+		}
+	}
+	return 0, false
+}
+
 // growright expands the underlying storage, if necessary
 func (s *Bitset[W, V]) growright(newSize uint) {
 	newLen, _ := indexToTuple[W](newSize)
@@ -254,16 +350,22 @@ func (a *Bitset[W, V]) Or(b *Bitset[W, V]) (aOrB *Bitset[W, V]) {
 // Inspection functions
 
 // Len is the used number of bits in the underlying data store (rounded up to word size).
-func (s *Bitset[V, W]) Len() int {
-	return len(s.bits) * int(unsafe.Sizeof(W(0))) * 8
+func (s *Bitset[W, V]) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.bits) * int(wordBits[W]())
 }
 
 // Cap is the available number of bits in the underlying data store (rounded up to word size).
-func (s *Bitset[V, W]) Cap() int {
-	return cap(s.bits) * int(unsafe.Sizeof(W(0))) * 8
+func (s *Bitset[W, V]) Cap() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return cap(s.bits) * int(wordBits[W]())
 }
 
 // Pop is the number of bits set in the underlying data store.
-func (s *Bitset[V, W]) Pop() uint {
+func (s *Bitset[W, V]) Pop() uint {
 	return s.pop
 }
@@ -1,9 +1,15 @@
 package bits
 
 import (
+	"context"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
 )
 
 func Test_Uint8s_Sizes(t *testing.T) {
@@ -92,3 +98,245 @@ func Test_Uint64_Inspect(t *testing.T) {
 	assert.GreaterOrEqual(t, a.Cap(), 64)
 	assert.Equal(t, uint(2), a.Pop())
 }
+
+func Test_Uint8_Inspect(t *testing.T) {
+	// Len and Cap once swapped their type parameters, and counted the value's size rather than the word's
+	a := New[uint8, uint64](0)
+	a.Set(1, 9)
+
+	assert.Equal(t, 16, a.Len())
+	assert.GreaterOrEqual(t, a.Cap(), 16)
+}
+
+func Test_Uint64_Page(t *testing.T) {
+	s := NewUint64(0)
+	s.Set(0, 1, 64, 65, 200, 201, 202)
+
+	vals, next, done := s.Page(bitset.Cursor[uint]{}, 3)
+	assert.Equal(t, []uint{0, 1, 64}, vals)
+	assert.False(t, done)
+
+	vals, next, done = s.Page(next, 3)
+	assert.Equal(t, []uint{65, 200, 201}, vals)
+	assert.False(t, done)
+	assert.False(t, next.Changed())
+
+	s.Unset(0)
+	vals, next, done = s.Page(next, 3)
+	assert.Equal(t, []uint{202}, vals)
+	assert.True(t, done)
+	assert.True(t, next.Changed())
+
+	vals, _, done = s.Page(bitset.After[uint](64), 0)
+	assert.Equal(t, []uint{65, 200, 201, 202}, vals)
+	assert.True(t, done)
+
+	b := New[uint8, uint8](0)
+	b.Set(254, 255)
+	bvals, _, done := b.Page(bitset.Cursor[uint8]{}, 2)
+	assert.Equal(t, []uint8{254, 255}, bvals)
+	assert.True(t, done)
+}
+
+func Test_Uint8_Navigate(t *testing.T) {
+	s := New[uint8, uint8](0)
+	s.Set(3, 4, 5, 40, 255)
+
+	next, ok := s.NextSet(6)
+	assert.True(t, ok)
+	assert.Equal(t, uint8(40), next)
+
+	prev, ok := s.PrevSet(39)
+	assert.True(t, ok)
+	assert.Equal(t, uint8(5), prev)
+
+	_, ok = s.PrevSet(2)
+	assert.False(t, ok)
+
+	clear, ok := s.NextClear(3)
+	assert.True(t, ok)
+	assert.Equal(t, uint8(6), clear)
+
+	_, ok = s.NextClear(255)
+	assert.False(t, ok)
+}
+
+func Test_Uint8_SetRange(t *testing.T) {
+	s := New[uint8, uint](0)
+	s.Set(5)
+	s.SetRange(3, 20)
+	assert.Equal(t, uint(18), s.Pop())
+	assert.Len(t, s.bits, 3)
+
+	s.UnsetRange(8, 15) // exactly one word
+	s.UnsetRange(19, 1000)
+	assert.Equal(t, "{3-7, 16-18}", s.String())
+	assert.Equal(t, uint(8), s.Pop())
+
+	v := s.Version()
+	s.SetRange(4, 6)
+	s.UnsetRange(100, 200)
+	assert.Equal(t, v, s.Version(), "no-ops don't change the version")
+}
+
+func Test_Uint16_Bools(t *testing.T) {
+	b := make([]bool, 40)
+	b[0], b[17], b[39] = true, true, true
+
+	s := FromBools[uint16, uint](b)
+	assert.Equal(t, "{0, 17, 39}", s.String())
+	assert.Equal(t, uint(3), s.Pop())
+	assert.Len(t, s.bits, 3)
+
+	unpacked := s.Bools()
+	assert.Len(t, unpacked, 48)
+	assert.Equal(t, b, unpacked[:40])
+}
+
+func Test_Uint64_Capacity(t *testing.T) {
+	s := New[uint64, uint](0)
+	s.Reserve(1000)
+	assert.Equal(t, 0, s.Len())
+	assert.Equal(t, 1024, s.Cap())
+
+	s.Set(5, 900)
+	assert.Equal(t, 960, s.Len())
+	assert.Equal(t, 1024, s.Cap(), "grown within the reservation")
+
+	s.Unset(900)
+	assert.Equal(t, 960, s.Len(), "no shrinking by default")
+	s.Trim()
+	assert.Equal(t, 64, s.Len())
+	assert.Equal(t, 64, s.Cap())
+
+	s.Set(1000)
+	s.Reset()
+	assert.Equal(t, 0, s.Len())
+	assert.GreaterOrEqual(t, s.Cap(), 1024, "capacity is kept")
+	assert.Equal(t, uint(0), s.Pop())
+	s.Set(64)
+	assert.Equal(t, []uint{64}, iterable.Values[uint](s), "reused words are zeroed")
+}
+
+func Test_Uint64_AutoShrink(t *testing.T) {
+	s := New[uint64, uint](64 * 100)
+	s.Set(3, 64*50, 64*99)
+	s.AutoShrink(bitset.DefaultShrink)
+
+	s.Unset(64 * 99)
+	assert.Equal(t, 64*51, s.Len(), "trailing empty words are dropped")
+	assert.Equal(t, 64*100, s.Cap(), "above the threshold")
+
+	s.UnsetRange(64, 64*60)
+	assert.Equal(t, 64, s.Len())
+	assert.Equal(t, 64, s.Cap(), "released below the threshold")
+	assert.Equal(t, uint(1), s.Pop())
+
+	c := s.Copy()
+	c.Set(64 * 10)
+	c.Unset(64 * 10)
+	assert.Equal(t, 64, c.Len(), "the policy is copied")
+}
+
+func Test_Uint64_Snapshot(t *testing.T) {
+	s := New[uint64, uint](0)
+	s.Set(1, 2, 40000)
+
+	snap := s.Snapshot()
+	s.Unset(2)
+	s.SetRange(100, 200)
+	s.Set(90000)
+	assert.True(t, snap.Get(2))
+	assert.False(t, snap.Get(150))
+	assert.False(t, snap.Get(90000))
+	assert.Equal(t, "{1, 2, 40000}", snap.String())
+	assert.Equal(t, uint(3), snap.Pop())
+
+	later := s.Snapshot()
+	s.Clear()
+	assert.Equal(t, []uint{1, 2, 40000}, iterable.Values[uint](snap))
+	assert.Equal(t, uint(104), later.Pop())
+	assert.True(t, later.Get(90000))
+
+	s.Set(7)
+	s.Snapshot()
+	s.Reset()
+	s.Set(8)
+	assert.Equal(t, []uint{8}, iterable.Values[uint](s), "reset isn't affected by snapshots")
+}
+
+func Test_Uint64_SnapshotRelease(t *testing.T) {
+	s := New[uint64, uint](0)
+	s.SetRange(0, 100000)
+	active := func() bool {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		return s.log.Active()
+	}
+
+	a, b := s.Snapshot(), s.Snapshot()
+	s.Unset(5)
+	a.Release()
+	a.Release()
+	assert.True(t, active(), "b is still live")
+	assert.True(t, b.Get(5))
+	b.Release()
+	assert.False(t, active())
+
+	// dropped snapshots are released by the garbage collector
+	s.Snapshot()
+	for i := 0; i < 100 && active(); i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	assert.False(t, active())
+}
+
+func Test_Uint64_UpdateConcurrent(t *testing.T) {
+	s := New[uint64, uint](0)
+	s.Set(0, 1000)
+
+	// each update moves both values, so observers only ever see two
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := uint(0); i < 1000; i++ {
+			assert.NoError(t, s.Update(func(tx bitset.Tx[uint]) error {
+				tx.Unset(i, i+1000)
+				tx.Set(i+1, i+1001)
+				return nil
+			}))
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		vals := iterable.Values[uint](s)
+		if assert.Len(t, vals, 2) {
+			assert.Equal(t, vals[0]+1000, vals[1])
+		}
+	}
+}
+
+func Test_Uint64_WaitConcurrent(t *testing.T) {
+	s := New[uint64, uint](0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := uint(0); i < 100; i++ {
+		wg.Add(1)
+		go func(i uint) {
+			defer wg.Done()
+			assert.NoError(t, s.WaitAll(ctx, i, i+100))
+		}(i)
+	}
+	for i := uint(0); i < 200; i++ {
+		s.Set(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,83 @@
+package bits
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Trim implements bitset.Capacity, dropping trailing empty words.
+func (s *Bitset[W, V]) Trim() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.trim()
+	s.compact()
+}
+
+// Reserve implements bitset.Capacity
+func (s *Bitset[W, V]) Reserve(n uint) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	wbits := wordBits[W]()
+	if words := int((n + wbits - 1) / wbits); words > cap(s.bits) {
+		bits := make([]W, len(s.bits), words)
+		copy(bits, s.bits)
+		s.bits = bits
+	}
+}
+
+// Reset implements bitset.Capacity. Unlike Clear, the words are kept, to be reused as the bitset grows again.
+func (s *Bitset[W, V]) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// growright appends zeroed words, so the old ones needn't be cleared, unless snapshots still read them
+	if s.log.Active() {
+		s.log.Freeze(s.bits)
+		s.bits = make([]W, 0, cap(s.bits))
+	} else {
+		s.bits = s.bits[:0]
+	}
+	s.pop = 0
+	s.version++
+}
+
+// AutoShrink implements bitset.Capacity. Utilisation is the words up to the last member, over the capacity.
+func (s *Bitset[W, V]) AutoShrink(below float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.shrink = below
+	s.autoshrink()
+}
+
+// trim drops trailing empty words.
+func (s *Bitset[W, V]) trim() {
+	n := len(s.bits)
+	for n > 0 && s.bits[n-1] == 0 {
+		n--
+	}
+	s.bits = s.bits[:n]
+}
+
+// compact releases any capacity beyond the words in use.
+func (s *Bitset[W, V]) compact() {
+	if cap(s.bits) > len(s.bits) {
+		bits := make([]W, len(s.bits))
+		copy(bits, s.bits)
+		s.bits = bits
+	}
+}
+
+// autoshrink applies the AutoShrink policy, after values are unset.
+func (s *Bitset[W, V]) autoshrink() {
+	if s.shrink <= 0 {
+		return
+	}
+	s.trim()
+	if float64(len(s.bits)) < s.shrink*float64(cap(s.bits)) {
+		s.compact()
+	}
+}
+
+var _ bitset.Capacity = (*Uint)(nil)
@@ -72,7 +72,55 @@ func (it *Iterator[W, V]) Next() (V, bool) {
 	return val, true
 }
 
+// IterateReverse implements iterable.Reversible
+func (s *Bitset[W, V]) IterateReverse() (iterable.Iter[V], uint) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	it := &ReverseIterator[W, V]{
+		Bitset: Bitset[W, V]{
+			lock: &sync.RWMutex{},
+			bits: make([]W, len(s.bits)),
+			pop:  s.pop,
+		},
+		done: len(s.bits) == 0,
+	}
+	copy(it.bits, s.bits)
+	if !it.done {
+		it.end = uint(len(s.bits))*wordBits[W]() - 1
+	}
+
+	return it, s.pop
+}
+
+// ReverseIterator enumerates a bitset in descending order.
+type ReverseIterator[W Width, V bitset.Value] struct {
+	Bitset[W, V]
+
+	end  uint // inclusive upper bound of the next value
+	done bool
+}
+
+func (it *ReverseIterator[W, V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.done {
+		return 0, false
+	}
+
+	idx, ok := it.prevSet(it.end)
+	if !ok || idx == 0 {
+		it.done = true
+	} else {
+		it.end = idx - 1
+	}
+	return V(idx), ok
+}
+
 var (
-	_ iterable.Iter[uint]     = (*Iterator[uint, uint])(nil)
-	_ iterable.Iterable[rune] = (*Bitset[uint, rune])(nil)
+	_ iterable.Iter[uint]       = (*Iterator[uint, uint])(nil)
+	_ iterable.Iter[uint]       = (*ReverseIterator[uint, uint])(nil)
+	_ iterable.Iterable[rune]   = (*Bitset[uint, rune])(nil)
+	_ iterable.Reversible[rune] = (*Bitset[uint, rune])(nil)
 )
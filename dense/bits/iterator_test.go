@@ -25,3 +25,16 @@ func TestIterate_Uint64(t *testing.T) {
 
 	assert.EqualValues(t, []uint{1, 2, 4, 6, 20, 21, 22, 23}, values)
 }
+
+func TestIterateReverse(t *testing.T) {
+	s := NewUint8(40)
+	s.Set(0, 1, 2, 4, 6, 7, 8)
+	s.Set(20, 21, 22, 23, 39)
+
+	assert.EqualValues(t, []uint{39, 23, 22, 21, 20, 8, 7, 6, 4, 2, 1, 0}, iterable.Values(iterable.Reverse[uint](s)))
+	assert.Empty(t, iterable.Values(iterable.Reverse[uint](NewUint64(0))))
+
+	u := NewUint64(0)
+	u.Set(63, 64, 200)
+	assert.EqualValues(t, []uint{200, 64, 63}, iterable.Values(iterable.Reverse[uint](u)))
+}
@@ -0,0 +1,29 @@
+package bits
+
+// NextSet returns the first member at or after from. Empty words are skipped over.
+func (s *Bitset[W, V]) NextSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.nextSet(uint(from))
+	return V(idx), ok
+}
+
+// PrevSet returns the last member at or before from. Empty words are skipped over.
+func (s *Bitset[W, V]) PrevSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.prevSet(uint(from))
+	return V(idx), ok
+}
+
+// NextClear returns the first unset position at or after from. Full words are skipped over.
+// It's only false if every position from there up to the largest V is set.
+func (s *Bitset[W, V]) NextClear(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx := s.nextClear(uint(from))
+	return V(idx), uint(V(idx)) == idx && idx >= uint(from)
+}
@@ -0,0 +1,38 @@
+package bits
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Version is incremented every time the bitset is modified.
+func (s *Bitset[W, V]) Version() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.version
+}
+
+// Page implements bitset.Pager
+func (s *Bitset[W, V]) Page(after bitset.Cursor[V], limit int) (vals []V, next bitset.Cursor[V], done bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	from, ok := after.Start()
+	if !ok {
+		return nil, after.Advance(nil, s.version), true
+	}
+
+	idx, ok := s.nextSet(uint(from))
+	for ; ok && (limit <= 0 || len(vals) < limit); idx, ok = s.nextSet(idx + 1) {
+		vals = append(vals, V(idx))
+		if V(idx+1) < V(idx) {
+			// end of the range of V
+			ok = false
+			break
+		}
+	}
+
+	return vals, after.Advance(vals, s.version), !ok
+}
+
+var _ bitset.Pager[uint] = (*Uint)(nil)
@@ -0,0 +1,182 @@
+package bits
+
+import (
+	"sync"
+
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+)
+
+// SetRange sets every value in [lo, hi], a word at a time.
+// The bitset will be expanded if necessary.
+func (s *Bitset[W, V]) SetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.setRange(lo, hi)
+}
+
+// UnsetRange unsets every value in [lo, hi], a word at a time.
+// Values outside of range are ignored.
+func (s *Bitset[W, V]) UnsetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.unsetRange(lo, hi)
+}
+
+func (s *Bitset[W, V]) setRange(lo, hi V) {
+	s.growright(uint(hi))
+	s.fill(uint(lo), uint(hi), true)
+	s.waiters.Notify()
+}
+
+func (s *Bitset[W, V]) unsetRange(lo, hi V) {
+	end := uint(len(s.bits)) * wordBits[W]()
+	if uint(lo) >= end {
+		return
+	}
+	if uint(hi) >= end {
+		hi = V(end - 1)
+	}
+	s.fill(uint(lo), uint(hi), false)
+	s.autoshrink()
+}
+
+// fill sets or clears [lo, hi], which must be within storage.
+func (s *Bitset[W, V]) fill(lo, hi uint, set bool) {
+	wbits := wordBits[W]()
+	s.log.Touch(s.bits, int(lo/wbits), int(hi/wbits))
+
+	pop := s.pop
+	for elem := lo / wbits; elem <= hi/wbits; elem++ {
+		mask := ^W(0)
+		if elem == lo/wbits {
+			mask &= ^W(0) << (lo % wbits)
+		}
+		if elem == hi/wbits {
+			mask &= ^W(0) >> (wbits - 1 - hi%wbits)
+		}
+
+		word := s.bits[elem]
+		if set {
+			s.pop += uint(mb.OnesCount64(uint64(mask &^ word)))
+			s.bits[elem] |= mask
+		} else {
+			s.pop -= uint(mb.OnesCount64(uint64(mask & word)))
+			s.bits[elem] &^= mask
+		}
+	}
+	if s.pop != pop {
+		s.version++
+	}
+}
+
+// FromBools packs a slice of booleans, where b[i] is whether i is a member, into a new bitset.
+func FromBools[W Width, V bitset.Value](b []bool) *Bitset[W, V] {
+	wbits := wordBits[W]()
+
+	s := &Bitset[W, V]{
+		lock: &sync.RWMutex{},
+		bits: make([]W, (uint(len(b))+wbits-1)/wbits),
+	}
+	for i, set := range b {
+		if set {
+			s.bits[uint(i)/wbits] |= 1 << (uint(i) % wbits)
+			s.pop++
+		}
+	}
+	return s
+}
+
+// FromWords makes a new bitset of words, where bit i of words[w] is member w*wordsize+i. The bitset takes ownership of
+// words.
+func FromWords[W Width, V bitset.Value](words []W) *Bitset[W, V] {
+	s := &Bitset[W, V]{
+		lock: &sync.RWMutex{},
+		bits: words,
+	}
+	for _, w := range words {
+		s.pop += uint(mb.OnesCount64(uint64(w)))
+	}
+	return s
+}
+
+// Bools unpacks the bitset into a new slice of booleans, where the ith element is whether i is a member.
+// Its length is a whole number of words.
+func (s *Bitset[W, V]) Bools() []bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	wbits := wordBits[W]()
+
+	b := make([]bool, uint(len(s.bits))*wbits)
+	for elem, word := range s.bits {
+		for word != 0 {
+			bit := uint(mb.TrailingZeros64(uint64(word)))
+			b[uint(elem)*wbits+bit] = true
+			word &= word - 1
+		}
+	}
+	return b
+}
+
+// OrMasked adds the members of src within [lo, hi] to s, a word at a time.
+// It reports false, and does nothing, unless src is a *Bitset[W, V] too. This lets callers which can't name W,
+// such as the ops package, use the word-level path.
+func (s *Bitset[W, V]) OrMasked(src any, lo, hi V) bool {
+	o, ok := src.(*Bitset[W, V])
+	if !ok {
+		return false
+	}
+	if lo > hi {
+		return true
+	}
+
+	wbits := wordBits[W]()
+	first := uint(lo) / wbits
+
+	// copy the masked words out first, so that both locks are never held at once
+	o.lock.RLock()
+	end := uint(len(o.bits)) * wbits
+	if uint(lo) >= end {
+		o.lock.RUnlock()
+		return true
+	}
+	if uint(hi) >= end {
+		hi = V(end - 1)
+	}
+	words := make([]W, uint(hi)/wbits-first+1)
+	copy(words, o.bits[first:])
+	o.lock.RUnlock()
+
+	words[0] &= ^W(0) << (uint(lo) % wbits)
+	words[len(words)-1] &= ^W(0) >> (wbits - 1 - uint(hi)%wbits)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.growright(uint(hi))
+	s.log.Touch(s.bits, int(first), int(first)+len(words)-1)
+
+	pop := s.pop
+	for i, w := range words {
+		add := w &^ s.bits[first+uint(i)]
+		s.bits[first+uint(i)] |= add
+		s.pop += uint(mb.OnesCount64(uint64(add)))
+	}
+	if s.pop != pop {
+		s.version++
+		s.waiters.Notify()
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package bits
+
+import (
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// snapshot is an unlocked copy of the bitset, for iterators to walk.
+func (s *Bitset[W, V]) snapshot() Bitset[W, V] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	clone := Bitset[W, V]{
+		lock: &sync.RWMutex{},
+		bits: make([]W, len(s.bits)),
+		pop:  s.pop,
+	}
+	copy(clone.bits, s.bits)
+	return clone
+}
+
+// IterateClear implements iterable.Runnable. Full words are skipped over.
+func (s *Bitset[W, V]) IterateClear(limit V) (iterable.Iter[V], uint) {
+	it := &ClearIterator[W, V]{
+		Bitset: s.snapshot(),
+		limit:  uint(limit),
+	}
+
+	size := uint(limit) + 1 // an upper bound. counting the members up to limit isn't worth it.
+	if size == 0 {
+		size--
+	}
+	return it, size
+}
+
+// ClearIterator enumerates the unset positions of a bitset.
+type ClearIterator[W Width, V bitset.Value] struct {
+	Bitset[W, V]
+
+	pos, limit uint
+	done       bool
+}
+
+func (it *ClearIterator[W, V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.done {
+		return 0, false
+	}
+
+	idx := it.nextClear(it.pos)
+	if idx > it.limit || idx < it.pos {
+		it.done = true
+		return 0, false
+	}
+	if idx == it.limit {
+		it.done = true
+	}
+	it.pos = idx + 1
+	return V(idx), true
+}
+
+// Runs implements iterable.Runnable. Runs are found a word at a time.
+func (s *Bitset[W, V]) Runs() iterable.Iter[iterable.Run[V]] {
+	return &RunIterator[W, V]{
+		Bitset: s.snapshot(),
+	}
+}
+
+// RunIterator enumerates the runs of a bitset.
+type RunIterator[W Width, V bitset.Value] struct {
+	Bitset[W, V]
+
+	pos  uint
+	done bool
+}
+
+func (it *RunIterator[W, V]) Next() (iterable.Run[V], bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.done {
+		return iterable.Run[V]{}, false
+	}
+
+	start := it.pos
+	if it.get(start) {
+		it.pos = it.nextClear(start)
+		return iterable.Run[V]{Start: V(start), End: V(it.pos - 1), Set: true}, true
+	}
+
+	next, ok := it.nextSet(start)
+	if !ok {
+		it.done = true
+		return iterable.Run[V]{}, false
+	}
+	it.pos = next
+	return iterable.Run[V]{Start: V(start), End: V(next - 1)}, true
+}
+
+var (
+	_ iterable.Iter[uint]               = (*ClearIterator[uint, uint])(nil)
+	_ iterable.Iter[iterable.Run[uint]] = (*RunIterator[uint, uint])(nil)
+	_ iterable.Runnable[rune]           = (*Bitset[uint, rune])(nil)
+)
@@ -0,0 +1,148 @@
+package bits
+
+import (
+	"runtime"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/cow"
+	"github.com/zblach/go-bitset/iterable"
+
+	mb "math/bits"
+)
+
+// Snapshot returns a read-only view of the bitset as it is now, in constant time. Unlike Copy, no words are
+// duplicated up front; each page of words is saved for the snapshot when it's first modified afterwards.
+func (s *Bitset[W, V]) Snapshot() *Snapshot[W, V] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snap := &Snapshot[W, V]{
+		src:     s,
+		gen:     s.log.Snapshot(),
+		words:   len(s.bits),
+		pop:     s.pop,
+		version: s.version,
+	}
+	// dropped snapshots are released when collected
+	runtime.SetFinalizer(snap, (*Snapshot[W, V]).Release)
+	return snap
+}
+
+// Snapshot is the members of a bitset at one point. It's unaffected by later modifications to the bitset, and is
+// safe for concurrent use.
+type Snapshot[W Width, V bitset.Value] struct {
+	src     *Bitset[W, V]
+	gen     *cow.Gen[W]
+	words   int
+	pop     uint
+	version uint64
+}
+
+// word returns word elem as of the snapshot. The source's read lock must be held.
+func (s *Snapshot[W, V]) word(elem int) W {
+	page := s.gen.Page(s.src.bits, elem/cow.PageSize)
+	if i := elem % cow.PageSize; i < len(page) {
+		return page[i]
+	}
+	// trimmed since, so empty then too
+	return 0
+}
+
+// Get returns whether index was a member.
+func (s *Snapshot[W, V]) Get(index V) bool {
+	elem, bit := indexToTuple[W](uint(index))
+	if elem >= uint(s.words) {
+		return false
+	}
+
+	s.src.lock.RLock()
+	defer s.src.lock.RUnlock()
+
+	return s.word(int(elem))&bit != 0
+}
+
+// Pop returns the number of members.
+func (s *Snapshot[W, V]) Pop() uint {
+	return s.pop
+}
+
+// Version returns the bitset's version when the snapshot was taken.
+func (s *Snapshot[W, V]) Version() uint64 {
+	return s.version
+}
+
+// Release stops the bitset saving its contents for the snapshot, which mustn't be used afterwards. A snapshot which
+// is dropped is released once it's garbage collected; Release just does so promptly.
+func (s *Snapshot[W, V]) Release() {
+	s.src.lock.Lock()
+	defer s.src.lock.Unlock()
+
+	if s.gen != nil {
+		s.src.log.Release(s.gen)
+		s.gen = nil
+	}
+}
+
+// Iterate implements iterable.Iterable. The words are copied a page at a time, as they're reached.
+func (s *Snapshot[W, V]) Iterate() (iterable.Iter[V], uint) {
+	return &SnapshotIterator[W, V]{snap: s}, s.pop
+}
+
+// String implements fmt.Stringer, e.g. {1, 3, 5-9}.
+func (s *Snapshot[W, V]) String() string {
+	it, _ := s.Iterate()
+	return bitset.String[V](it)
+}
+
+// SnapshotIterator enumerates a snapshot in ascending order.
+type SnapshotIterator[W Width, V bitset.Value] struct {
+	snap  *Snapshot[W, V]
+	page  []W
+	elem  int // index of the first word in page
+	index int // of the next word to examine in page
+	bits  W   // unvisited bits of the current word
+	cur   int // index of the current word
+}
+
+func (it *SnapshotIterator[W, V]) Next() (V, bool) {
+	for it.bits == 0 {
+		if it.index == len(it.page) && !it.load() {
+			return 0, false
+		}
+		it.bits, it.cur = it.page[it.index], it.elem+it.index
+		it.index++
+	}
+
+	bit := uint(mb.TrailingZeros64(uint64(it.bits)))
+	it.bits &= it.bits - 1
+	return V(uint(it.cur)*wordBits[W]() + bit), true
+}
+
+// load copies the next page of words, reporting whether there was one.
+func (it *SnapshotIterator[W, V]) load() bool {
+	it.elem += len(it.page)
+	if it.page == nil {
+		it.elem = 0
+	}
+	if it.elem >= it.snap.words {
+		return false
+	}
+
+	n := it.snap.words - it.elem
+	if n > cow.PageSize {
+		n = cow.PageSize
+	}
+	page := make([]W, n) // zero beyond what's left, if trimmed since
+
+	it.snap.src.lock.RLock()
+	copy(page, it.snap.gen.Page(it.snap.src.bits, it.elem/cow.PageSize))
+	it.snap.src.lock.RUnlock()
+
+	it.page, it.index = page, 0
+	return true
+}
+
+var (
+	_ iterable.Iterable[uint] = (*Snapshot[uint, uint])(nil)
+	_ iterable.Iter[uint]     = (*SnapshotIterator[uint, uint])(nil)
+)
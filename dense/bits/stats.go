@@ -0,0 +1,21 @@
+package bits
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Bytes implements bitset.Inspect: the bitset, its lock, and its words up to their capacity.
+func (s *Bitset[W, V]) Bytes() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return int(unsafe.Sizeof(*s)+unsafe.Sizeof(*s.lock)) + cap(s.bits)*int(unsafe.Sizeof(W(0)))
+}
+
+// Stats implements bitset.Inspect. Runs are found a word at a time.
+func (s *Bitset[W, V]) Stats() bitset.Stats[V] {
+	return iterable.Measure(s.Bytes(), s.Runs())
+}
@@ -0,0 +1,23 @@
+package bits
+
+import (
+	"context"
+
+	"github.com/zblach/go-bitset/internal/wait"
+)
+
+// WaitFor blocks until v is a member, or ctx is done, in which case ctx.Err() is returned.
+func (s *Bitset[W, V]) WaitFor(ctx context.Context, v V) error {
+	return wait.All(ctx, s.lock, &s.waiters, func(v V) bool { return s.get(uint(v)) }, []V{v})
+}
+
+// WaitAll blocks until every one of vs is a member at once, or ctx is done, in which case ctx.Err() is returned.
+func (s *Bitset[W, V]) WaitAll(ctx context.Context, vs ...V) error {
+	return wait.All(ctx, s.lock, &s.waiters, func(v V) bool { return s.get(uint(v)) }, vs)
+}
+
+// WaitAny blocks until one of vs is a member, and returns it, or until ctx is done, in which case ctx.Err() is
+// returned. With no vs, it waits for ctx.
+func (s *Bitset[W, V]) WaitAny(ctx context.Context, vs ...V) (V, error) {
+	return wait.Any(ctx, s.lock, &s.waiters, func(v V) bool { return s.get(uint(v)) }, vs)
+}
@@ -4,6 +4,8 @@ import (
 	"sync"
 
 	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/cow"
+	"github.com/zblach/go-bitset/internal/wait"
 	"github.com/zblach/go-bitset/mixin/logical"
 )
 
@@ -14,7 +16,11 @@ type Bitset[V bitset.Value] struct {
 	lock *sync.RWMutex
 	bits []bool
 
-	pop uint
+	pop     uint
+	version uint64        // incremented on every modification
+	shrink  float64       // AutoShrink threshold, or 0
+	log     cow.Log[bool] // original values, for snapshots
+	waiters wait.Signal   // woken as members are added
 }
 
 // New creates a new boolean bitset with an initial size of size.
@@ -29,16 +35,20 @@ func (s *Bitset[V]) Clear() {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	s.log.Freeze(s.bits)
 	s.bits = make([]bool, 0)
 	s.pop = 0
+	s.version++
 }
 
 func (s *Bitset[V]) Copy() *Bitset[V] {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
-	clone := New[V](s.pop)
+	clone := New[V](uint(len(s.bits)))
 	copy(clone.bits, s.bits)
+	clone.pop = s.pop
+	clone.shrink = s.shrink
 
 	return clone
 }
@@ -49,10 +59,11 @@ func (s *Bitset[V]) Get(index V) bool {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
-	if uint(index) >= uint(len(s.bits)) {
-		return false
-	}
-	return s.bits[index]
+	return s.get(index)
+}
+
+func (s *Bitset[V]) get(index V) bool {
+	return uint(index) < uint(len(s.bits)) && s.bits[index]
 }
 
 // Set one or more values in the bitset.
@@ -74,12 +85,18 @@ func (s *Bitset[V]) Set(indices ...V) {
 
 	s.growright(uint64(maxIndex))
 
+	pop := s.pop
 	for _, index := range indices {
 		if !s.bits[index] {
+			s.log.Touch(s.bits, int(index), int(index))
 			s.bits[index] = true
 			s.pop += 1
 		}
 	}
+	if s.pop != pop {
+		s.version++
+		s.waiters.Notify()
+	}
 }
 
 // Unset one or more values in the bitset.
@@ -92,15 +109,21 @@ func (s *Bitset[V]) Unset(indices ...V) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	pop := s.pop
 	for _, index := range indices {
 		if uint(index) >= uint(len(s.bits)) {
 			continue
 		}
 		if s.bits[index] {
+			s.log.Touch(s.bits, int(index), int(index))
 			s.bits[index] = false
 			s.pop -= 1
 		}
 	}
+	if s.pop != pop {
+		s.autoshrink()
+		s.version++
+	}
 }
 
 // growright expands the underlying storage, if necessary
@@ -174,13 +197,27 @@ func (a *Bitset[V]) Or(b *Bitset[V]) (aOrB *Bitset[V]) {
 
 var _ bitset.Binary[uint, *Bitset[uint]] = (*Bitset[uint])(nil)
 
+// Len is the number of values in the underlying slice.
 func (b *Bitset[V]) Len() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
 	return len(b.bits)
 }
+
+// Cap is the number of values the underlying slice can hold without reallocating.
 func (b *Bitset[V]) Cap() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
 	return cap(b.bits)
 }
+
+// Pop is the number of values set.
 func (b *Bitset[V]) Pop() uint {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
 	return b.pop
 }
 
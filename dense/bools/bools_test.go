@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
 )
 
 func Test_Bools_Sizes(t *testing.T) {
@@ -88,3 +90,111 @@ func Test_Bools_Inspect(t *testing.T) {
 	assert.GreaterOrEqual(t, a.Cap(), 4)
 	assert.Equal(t, uint(2), a.Pop())
 }
+
+func Test_Bools_Page(t *testing.T) {
+	s := New[uint](0)
+	s.Set(0, 1, 64, 65, 200, 201, 202)
+
+	vals, next, done := s.Page(bitset.Cursor[uint]{}, 3)
+	assert.Equal(t, []uint{0, 1, 64}, vals)
+	assert.False(t, done)
+
+	vals, next, done = s.Page(next, 4)
+	assert.Equal(t, []uint{65, 200, 201, 202}, vals)
+	assert.True(t, done)
+	assert.False(t, next.Changed())
+
+	s.Set(300)
+	vals, next, done = s.Page(next, 4)
+	assert.Equal(t, []uint{300}, vals)
+	assert.True(t, done)
+	assert.True(t, next.Changed())
+}
+
+func Test_Bools_IterateReverse(t *testing.T) {
+	s := New[uint](0)
+	s.Set(0, 3, 4, 10)
+
+	assert.Equal(t, []uint{10, 4, 3, 0}, iterable.Values(iterable.Reverse[uint](s)))
+}
+
+func Test_Bools_Copy(t *testing.T) {
+	a := New[uint](0)
+	a.Set(1, 2, 6)
+
+	b := a.Copy()
+	b.Set(10)
+
+	assert.Equal(t, "{1, 2, 6}", a.String())
+	assert.Equal(t, "{1, 2, 6, 10}", b.String())
+	assert.Equal(t, uint(4), b.Pop())
+
+	// copies were once sized by the population, so lost the members beyond it
+	c := New[uint](0)
+	c.Set(100)
+	assert.Equal(t, "{100}", c.Copy().String())
+}
+
+func Test_Bools_SetRange(t *testing.T) {
+	s := New[uint](0)
+	s.SetRange(3, 9)
+	s.UnsetRange(5, 100)
+	assert.Equal(t, "{3, 4}", s.String())
+	assert.Equal(t, uint(2), s.Pop())
+
+	f := FromSlice[uint]([]bool{false, true, true})
+	assert.Equal(t, uint(2), f.Pop())
+	f.View(func(b []bool) { assert.Equal(t, []bool{false, true, true}, b) })
+}
+
+func Test_Bools_Capacity(t *testing.T) {
+	s := New[uint](0)
+	s.Reserve(100)
+	assert.Equal(t, 0, s.Len())
+	assert.Equal(t, 100, s.Cap())
+
+	s.Set(3, 50)
+	s.Unset(50)
+	assert.Equal(t, 51, s.Len(), "no shrinking by default")
+	s.Trim()
+	assert.Equal(t, 4, s.Len())
+	assert.Equal(t, 4, s.Cap())
+
+	s.Set(99)
+	s.Reset()
+	assert.Equal(t, 0, s.Len())
+	assert.GreaterOrEqual(t, s.Cap(), 100)
+	s.Set(10)
+	assert.Equal(t, []uint{10}, iterable.Values[uint](s))
+
+	s.AutoShrink(bitset.DefaultShrink)
+	s.Set(90)
+	s.Unset(90)
+	assert.Equal(t, 11, s.Len())
+	assert.Equal(t, 11, s.Cap())
+}
+
+func Test_Bools_Snapshot(t *testing.T) {
+	s := New[uint](0)
+	s.Set(1, 2, 4000)
+
+	snap := s.Snapshot()
+	s.Unset(2)
+	s.SetRange(100, 200)
+	s.Set(9000)
+	assert.True(t, snap.Get(2))
+	assert.False(t, snap.Get(150))
+	assert.False(t, snap.Get(9000))
+	assert.Equal(t, "{1, 2, 4000}", snap.String())
+
+	s.AutoShrink(bitset.DefaultShrink)
+	s.UnsetRange(0, 10000)
+	assert.Equal(t, []uint{1, 2, 4000}, iterable.Values[uint](snap), "trimmed values are kept")
+
+	s.Set(5)
+	later := s.Snapshot()
+	s.Reset()
+	s.Set(6)
+	assert.Equal(t, []uint{5}, iterable.Values[uint](later))
+	assert.Equal(t, []uint{6}, iterable.Values[uint](s))
+}
@@ -0,0 +1,82 @@
+package bools
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Trim implements bitset.Capacity, dropping trailing unset values.
+func (s *Bitset[V]) Trim() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.trim()
+	s.compact()
+}
+
+// Reserve implements bitset.Capacity
+func (s *Bitset[V]) Reserve(n uint) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if n > uint(cap(s.bits)) {
+		bits := make([]bool, len(s.bits), n)
+		copy(bits, s.bits)
+		s.bits = bits
+	}
+}
+
+// Reset implements bitset.Capacity. Unlike Clear, the slice is kept, to be reused as the bitset grows again.
+func (s *Bitset[V]) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// growright appends false values, so the old ones needn't be cleared, unless snapshots still read them
+	if s.log.Active() {
+		s.log.Freeze(s.bits)
+		s.bits = make([]bool, 0, cap(s.bits))
+	} else {
+		s.bits = s.bits[:0]
+	}
+	s.pop = 0
+	s.version++
+}
+
+// AutoShrink implements bitset.Capacity. Utilisation is the values up to the last member, over the capacity.
+func (s *Bitset[V]) AutoShrink(below float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.shrink = below
+	s.autoshrink()
+}
+
+// trim drops trailing unset values.
+func (s *Bitset[V]) trim() {
+	n := len(s.bits)
+	for n > 0 && !s.bits[n-1] {
+		n--
+	}
+	s.bits = s.bits[:n]
+}
+
+// compact releases any capacity beyond the values in use.
+func (s *Bitset[V]) compact() {
+	if cap(s.bits) > len(s.bits) {
+		bits := make([]bool, len(s.bits))
+		copy(bits, s.bits)
+		s.bits = bits
+	}
+}
+
+// autoshrink applies the AutoShrink policy, after values are unset.
+func (s *Bitset[V]) autoshrink() {
+	if s.shrink <= 0 {
+		return
+	}
+	s.trim()
+	if float64(len(s.bits)) < s.shrink*float64(cap(s.bits)) {
+		s.compact()
+	}
+}
+
+var _ bitset.Capacity = (*Bitset[uint])(nil)
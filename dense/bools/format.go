@@ -0,0 +1,17 @@
+package bools
+
+import (
+	"fmt"
+
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// String implements fmt.Stringer, e.g. {1, 3, 5-9}.
+func (s *Bitset[V]) String() string {
+	return iterable.String(s.Runs())
+}
+
+// Format implements fmt.Formatter. See bitset.Format for the supported verbs. Members are read a run at a time.
+func (s *Bitset[V]) Format(f fmt.State, verb rune) {
+	iterable.Format[V](f, verb, s.Runs(), s)
+}
@@ -39,7 +39,45 @@ func (it *Iterator[V]) Next() (V, bool) {
 	return 0, false
 }
 
+// IterateReverse implements iterable.Reversible
+func (s *Bitset[V]) IterateReverse() (iterable.Iter[V], uint) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	len := uint(len(s.bits))
+
+	it := &ReverseIterator[V]{
+		Bitset: *New[V](len),
+		index:  len,
+	}
+	it.pop = s.pop
+	copy(it.bits, s.bits)
+
+	return it, len
+}
+
+// ReverseIterator enumerates a bitset in descending order.
+type ReverseIterator[V bitset.Value] struct {
+	Bitset[V]
+	index uint // one past the next candidate
+}
+
+func (it *ReverseIterator[V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	for it.index > 0 {
+		it.index--
+		if it.bits[it.index] {
+			return V(it.index), true
+		}
+	}
+	return 0, false
+}
+
 var (
-	_ iterable.Iter[uint]     = (*Iterator[uint])(nil)
-	_ iterable.Iterable[rune] = (*Bitset[rune])(nil)
+	_ iterable.Iter[uint]       = (*Iterator[uint])(nil)
+	_ iterable.Iter[uint]       = (*ReverseIterator[uint])(nil)
+	_ iterable.Iterable[rune]   = (*Bitset[rune])(nil)
+	_ iterable.Reversible[rune] = (*Bitset[rune])(nil)
 )
@@ -0,0 +1,41 @@
+package bools
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Version is incremented every time the bitset is modified.
+func (s *Bitset[V]) Version() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.version
+}
+
+// Page implements bitset.Pager
+func (s *Bitset[V]) Page(after bitset.Cursor[V], limit int) (vals []V, next bitset.Cursor[V], done bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	from, ok := after.Start()
+	if !ok {
+		return nil, after.Advance(nil, s.version), true
+	}
+
+	i := uint(from)
+	for ; i < uint(len(s.bits)) && (limit <= 0 || len(vals) < limit); i++ {
+		if s.bits[i] {
+			vals = append(vals, V(i))
+		}
+	}
+
+	// skip ahead to see if anything remains
+	for ; i < uint(len(s.bits)); i++ {
+		if s.bits[i] {
+			return vals, after.Advance(vals, s.version), false
+		}
+	}
+	return vals, after.Advance(vals, s.version), true
+}
+
+var _ bitset.Pager[uint] = (*Bitset[uint])(nil)
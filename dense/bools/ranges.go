@@ -0,0 +1,94 @@
+package bools
+
+import (
+	"sync"
+
+	"github.com/zblach/go-bitset"
+)
+
+// SetRange sets every value in [lo, hi].
+// The bitset will be expanded if necessary.
+func (s *Bitset[V]) SetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.setRange(lo, hi)
+}
+
+// UnsetRange unsets every value in [lo, hi].
+// Values outside of range are ignored.
+func (s *Bitset[V]) UnsetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.unsetRange(lo, hi)
+}
+
+// fill sets or clears [lo, hi], which must be within storage.
+func (s *Bitset[V]) fill(lo, hi uint64, set bool) {
+	s.log.Touch(s.bits, int(lo), int(hi))
+
+	pop := s.pop
+	for i := lo; i <= hi; i++ {
+		if s.bits[i] != set {
+			s.bits[i] = set
+			if set {
+				s.pop++
+			} else {
+				s.pop--
+			}
+		}
+	}
+	if s.pop != pop {
+		s.version++
+	}
+}
+
+// FromSlice creates a bitset which takes ownership of b, where b[i] is whether i is a member.
+func FromSlice[V bitset.Value](b []bool) *Bitset[V] {
+	s := &Bitset[V]{
+		lock: &sync.RWMutex{},
+		bits: b,
+	}
+	for _, set := range b {
+		if set {
+			s.pop++
+		}
+	}
+	return s
+}
+
+// View calls fn with the underlying storage, while holding the read lock.
+// fn must neither modify nor retain the slice.
+func (s *Bitset[V]) View(fn func(b []bool)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	fn(s.bits)
+}
+
+func (s *Bitset[V]) setRange(lo, hi V) {
+	s.growright(uint64(hi))
+	s.fill(uint64(lo), uint64(hi), true)
+	s.waiters.Notify()
+}
+
+func (s *Bitset[V]) unsetRange(lo, hi V) {
+	end := uint64(len(s.bits))
+	if uint64(lo) >= end {
+		return
+	}
+	if uint64(hi) >= end {
+		hi = V(end - 1)
+	}
+	s.fill(uint64(lo), uint64(hi), false)
+	s.autoshrink()
+}
@@ -0,0 +1,17 @@
+package bools
+
+import (
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// IterateClear implements iterable.Runnable
+func (s *Bitset[V]) IterateClear(limit V) (iterable.Iter[V], uint) {
+	return iterable.Complement[V](s, limit).Iterate()
+}
+
+// Runs implements iterable.Runnable
+func (s *Bitset[V]) Runs() iterable.Iter[iterable.Run[V]] {
+	return iterable.RunsOf[V](s)
+}
+
+var _ iterable.Runnable[rune] = (*Bitset[rune])(nil)
@@ -0,0 +1,137 @@
+package bools
+
+import (
+	"runtime"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/cow"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Snapshot returns a read-only view of the bitset as it is now, in constant time. Unlike Copy, nothing is
+// duplicated up front; each page of values is saved for the snapshot when it's first modified afterwards.
+func (s *Bitset[V]) Snapshot() *Snapshot[V] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snap := &Snapshot[V]{
+		src:     s,
+		gen:     s.log.Snapshot(),
+		len:     len(s.bits),
+		pop:     s.pop,
+		version: s.version,
+	}
+	// dropped snapshots are released when collected
+	runtime.SetFinalizer(snap, (*Snapshot[V]).Release)
+	return snap
+}
+
+// Snapshot is the members of a bitset at one point. It's unaffected by later modifications to the bitset, and is
+// safe for concurrent use.
+type Snapshot[V bitset.Value] struct {
+	src     *Bitset[V]
+	gen     *cow.Gen[bool]
+	len     int
+	pop     uint
+	version uint64
+}
+
+// Get returns whether index was a member.
+func (s *Snapshot[V]) Get(index V) bool {
+	if uint(index) >= uint(s.len) {
+		return false
+	}
+
+	s.src.lock.RLock()
+	defer s.src.lock.RUnlock()
+
+	page := s.gen.Page(s.src.bits, int(index)/cow.PageSize)
+	// beyond the page if trimmed since, so unset then too
+	i := int(index) % cow.PageSize
+	return i < len(page) && page[i]
+}
+
+// Pop returns the number of members.
+func (s *Snapshot[V]) Pop() uint {
+	return s.pop
+}
+
+// Version returns the bitset's version when the snapshot was taken.
+func (s *Snapshot[V]) Version() uint64 {
+	return s.version
+}
+
+// Release stops the bitset saving its contents for the snapshot, which mustn't be used afterwards. A snapshot which
+// is dropped is released once it's garbage collected; Release just does so promptly.
+func (s *Snapshot[V]) Release() {
+	s.src.lock.Lock()
+	defer s.src.lock.Unlock()
+
+	if s.gen != nil {
+		s.src.log.Release(s.gen)
+		s.gen = nil
+	}
+}
+
+// Iterate implements iterable.Iterable. The values are copied a page at a time, as they're reached.
+func (s *Snapshot[V]) Iterate() (iterable.Iter[V], uint) {
+	return &SnapshotIterator[V]{snap: s}, s.pop
+}
+
+// String implements fmt.Stringer, e.g. {1, 3, 5-9}.
+func (s *Snapshot[V]) String() string {
+	it, _ := s.Iterate()
+	return bitset.String[V](it)
+}
+
+// SnapshotIterator enumerates a snapshot in ascending order.
+type SnapshotIterator[V bitset.Value] struct {
+	snap   *Snapshot[V]
+	page   []bool
+	start  int // value of page[0]
+	index  int // of the next value to examine in page
+	loaded bool
+}
+
+func (it *SnapshotIterator[V]) Next() (V, bool) {
+	for {
+		for ; it.index < len(it.page); it.index++ {
+			if it.page[it.index] {
+				it.index++
+				return V(it.start + it.index - 1), true
+			}
+		}
+		if !it.load() {
+			return 0, false
+		}
+	}
+}
+
+// load copies the next page of values, reporting whether there was one.
+func (it *SnapshotIterator[V]) load() bool {
+	if it.loaded {
+		it.start += cow.PageSize
+	}
+	it.loaded = true
+	if it.start >= it.snap.len {
+		return false
+	}
+
+	n := it.snap.len - it.start
+	if n > cow.PageSize {
+		n = cow.PageSize
+	}
+	page := make([]bool, n) // false beyond what's left, if trimmed since
+
+	it.snap.src.lock.RLock()
+	copy(page, it.snap.gen.Page(it.snap.src.bits, it.start/cow.PageSize))
+	it.snap.src.lock.RUnlock()
+
+	it.page, it.index = page, 0
+	return true
+}
+
+var (
+	_ iterable.Iterable[uint] = (*Snapshot[uint])(nil)
+	_ iterable.Iter[uint]     = (*SnapshotIterator[uint])(nil)
+)
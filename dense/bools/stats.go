@@ -0,0 +1,21 @@
+package bools
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Bytes implements bitset.Inspect: the bitset, its lock, and a byte for every value up to the slice's capacity.
+func (s *Bitset[V]) Bytes() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return int(unsafe.Sizeof(*s)+unsafe.Sizeof(*s.lock)) + cap(s.bits)
+}
+
+// Stats implements bitset.Inspect
+func (s *Bitset[V]) Stats() bitset.Stats[V] {
+	return iterable.Measure(s.Bytes(), s.Runs())
+}
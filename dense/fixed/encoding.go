@@ -0,0 +1,100 @@
+package fixed
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/codec"
+)
+
+// MarshalJSON implements json.Marshaler, as an array of members. See codec.JSON for the other encodings.
+func (s set[V, A]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON[V](s, codec.Members)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting any of codec's JSON encodings.
+func (s *set[V, A]) UnmarshalJSON(data []byte) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.UnmarshalJSON(dst, data) })
+}
+
+// MarshalText implements encoding.TextMarshaler, as a list of members and ranges: 1,3,5-9
+func (s set[V, A]) MarshalText() ([]byte, error) {
+	return codec.MarshalText[V](s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *set[V, A]) UnmarshalText(text []byte) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.UnmarshalText(dst, text) })
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, in codec's binary format.
+func (s set[V, A]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary[V](s), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *set[V, A]) UnmarshalBinary(data []byte) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.UnmarshalBinary(dst, data) })
+}
+
+// Value implements driver.Valuer, for bytea columns. See codec.SQL for bit varying.
+func (s set[V, A]) Value() (driver.Value, error) {
+	return codec.Value[V](s, codec.Bytea)
+}
+
+// Scan implements sql.Scanner, accepting either of codec's SQL encodings.
+func (s *set[V, A]) Scan(src any) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.Scan(dst, src) })
+}
+
+// decode runs a decoder against a copy of the set, which replaces it if every value fits.
+func (s *set[V, A]) decode(fn func(dst bitset.Bitset[V]) error) error {
+	b := &bounded[V, A]{set: *s}
+	if err := fn(b); err != nil {
+		return err
+	}
+	if b.err != nil {
+		return b.err
+	}
+	*s = b.set
+	return nil
+}
+
+// bounded records values beyond the capacity of the set, instead of panicking, and ignores everything after the first.
+type bounded[V bitset.Value, A words] struct {
+	set[V, A]
+	err error
+}
+
+// Limit lets the codec reject members beyond the capacity before setting any.
+func (b *bounded[V, A]) Limit() uint64 {
+	return uint64(b.Len())
+}
+
+func (b *bounded[V, A]) Set(indices ...V) {
+	for _, index := range indices {
+		if !b.fits(index) {
+			return
+		}
+	}
+	b.set.Set(indices...)
+}
+
+func (b *bounded[V, A]) SetRange(lo, hi V) {
+	if b.fits(hi) {
+		b.set.SetRange(lo, hi)
+	}
+}
+
+// fits reports whether index is within the capacity, recording the error if it's the first that isn't.
+func (b *bounded[V, A]) fits(index V) bool {
+	if b.err != nil {
+		return false
+	}
+	if uint(index) >= uint(b.Len()) {
+		b.err = fmt.Errorf("%w: %d is beyond the set's capacity of %d", codec.ErrMalformed, index, b.Len())
+		return false
+	}
+	return true
+}
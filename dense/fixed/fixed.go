@@ -0,0 +1,229 @@
+package fixed
+
+import (
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+)
+
+const wordBits = 64
+
+// words are the supported backing arrays.
+type words interface {
+	[1]uint64 | [2]uint64 | [4]uint64
+}
+
+// set implements everything that doesn't depend on the concrete type of the set. It's embedded by each size.
+// It contains no pointers, so it's comparable, and can be copied freely.
+type set[V bitset.Value, A words] struct {
+	w A
+}
+
+// Get returns whether or not a value is set. Values beyond the capacity of the set are never set.
+func (s set[V, A]) Get(index V) bool {
+	i := uint(index)
+	if i >= uint(len(s.w))*wordBits {
+		return false
+	}
+	return s.w[i/wordBits]&(1<<(i%wordBits)) != 0
+}
+
+// Set one or more values. Fixed sets can't grow, so setting a value beyond their capacity panics.
+func (s *set[V, A]) Set(indices ...V) {
+	for _, index := range indices {
+		i := uint(index)
+		if i >= uint(len(s.w))*wordBits {
+			panic("fixed: index out of range")
+		}
+		s.w[i/wordBits] |= 1 << (i % wordBits)
+	}
+}
+
+// Unset one or more values. Values beyond the capacity of the set are ignored.
+func (s *set[V, A]) Unset(indices ...V) {
+	for _, index := range indices {
+		i := uint(index)
+		if i < uint(len(s.w))*wordBits {
+			s.w[i/wordBits] &^= 1 << (i % wordBits)
+		}
+	}
+}
+
+// SetRange sets every value in [lo, hi], a word at a time. Like Set, it panics if hi is beyond the capacity.
+func (s *set[V, A]) SetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+	if uint(hi) >= uint(len(s.w))*wordBits {
+		panic("fixed: index out of range")
+	}
+	s.mask(uint(lo), uint(hi), true)
+}
+
+// UnsetRange unsets every value in [lo, hi], a word at a time. Values beyond the capacity of the set are ignored.
+func (s *set[V, A]) UnsetRange(lo, hi V) {
+	limit := uint(len(s.w)) * wordBits
+	if lo > hi || uint(lo) >= limit {
+		return
+	}
+	last := uint(hi)
+	if last >= limit {
+		last = limit - 1
+	}
+	s.mask(uint(lo), last, false)
+}
+
+// mask sets or unsets [lo, hi], which must be within the capacity.
+func (s *set[V, A]) mask(lo, hi uint, on bool) {
+	for elem := lo / wordBits; elem <= hi/wordBits; elem++ {
+		m := ^uint64(0)
+		if start := elem * wordBits; lo > start {
+			m &= ^uint64(0) << (lo - start)
+		}
+		if end := elem*wordBits + wordBits - 1; hi < end {
+			m &= ^uint64(0) >> (end - hi)
+		}
+		if on {
+			s.w[elem] |= m
+		} else {
+			s.w[elem] &^= m
+		}
+	}
+}
+
+// Clear unsets all values.
+func (s *set[V, A]) Clear() {
+	var zero A
+	s.w = zero
+}
+
+func (a set[V, A]) and(b set[V, A]) set[V, A] {
+	for i := 0; i < len(a.w); i++ {
+		a.w[i] &= b.w[i]
+	}
+	return a
+}
+
+func (a set[V, A]) or(b set[V, A]) set[V, A] {
+	for i := 0; i < len(a.w); i++ {
+		a.w[i] |= b.w[i]
+	}
+	return a
+}
+
+func (s set[V, A]) with(indices []V) set[V, A] {
+	s.Set(indices...)
+	return s
+}
+
+func (s set[V, A]) without(indices []V) set[V, A] {
+	s.Unset(indices...)
+	return s
+}
+
+// Inspection functions
+
+// Len is the number of values the set can hold.
+func (s set[V, A]) Len() int {
+	return len(s.w) * wordBits
+}
+
+// Cap is the number of values the set can hold. It's the same as Len.
+func (s set[V, A]) Cap() int {
+	return len(s.w) * wordBits
+}
+
+// Pop is the number of values set.
+func (s set[V, A]) Pop() (pop uint) {
+	for i := 0; i < len(s.w); i++ {
+		pop += uint(mb.OnesCount64(s.w[i]))
+	}
+	return
+}
+
+// Set64 is an allocation-free set of the values [0, 64).
+// It's a comparable value type, so it can be used as a map key, and With and Without return modified copies.
+type Set64[V bitset.Value] struct {
+	set[V, [1]uint64]
+}
+
+// Set128 is an allocation-free set of the values [0, 128).
+// It's a comparable value type, so it can be used as a map key, and With and Without return modified copies.
+type Set128[V bitset.Value] struct {
+	set[V, [2]uint64]
+}
+
+// Set256 is an allocation-free set of the values [0, 256).
+// It's a comparable value type, so it can be used as a map key, and With and Without return modified copies.
+type Set256[V bitset.Value] struct {
+	set[V, [4]uint64]
+}
+
+// With returns a copy of s with the values added.
+func (s Set64[V]) With(indices ...V) Set64[V] { return Set64[V]{s.with(indices)} }
+
+// Without returns a copy of s with the values removed.
+func (s Set64[V]) Without(indices ...V) Set64[V] { return Set64[V]{s.without(indices)} }
+
+// Intersect returns the intersection of two sets, by value.
+func (a Set64[V]) Intersect(b Set64[V]) Set64[V] { return Set64[V]{a.and(b.set)} }
+
+// Union returns the union of two sets, by value.
+func (a Set64[V]) Union(b Set64[V]) Set64[V] { return Set64[V]{a.or(b.set)} }
+
+// And implements bitset.Binary. Prefer Intersect, which doesn't allocate.
+func (a *Set64[V]) And(b *Set64[V]) *Set64[V] { r := a.Intersect(*b); return &r }
+
+// Or implements bitset.Binary. Prefer Union, which doesn't allocate.
+func (a *Set64[V]) Or(b *Set64[V]) *Set64[V] { r := a.Union(*b); return &r }
+
+// With returns a copy of s with the values added.
+func (s Set128[V]) With(indices ...V) Set128[V] { return Set128[V]{s.with(indices)} }
+
+// Without returns a copy of s with the values removed.
+func (s Set128[V]) Without(indices ...V) Set128[V] { return Set128[V]{s.without(indices)} }
+
+// Intersect returns the intersection of two sets, by value.
+func (a Set128[V]) Intersect(b Set128[V]) Set128[V] { return Set128[V]{a.and(b.set)} }
+
+// Union returns the union of two sets, by value.
+func (a Set128[V]) Union(b Set128[V]) Set128[V] { return Set128[V]{a.or(b.set)} }
+
+// And implements bitset.Binary. Prefer Intersect, which doesn't allocate.
+func (a *Set128[V]) And(b *Set128[V]) *Set128[V] { r := a.Intersect(*b); return &r }
+
+// Or implements bitset.Binary. Prefer Union, which doesn't allocate.
+func (a *Set128[V]) Or(b *Set128[V]) *Set128[V] { r := a.Union(*b); return &r }
+
+// With returns a copy of s with the values added.
+func (s Set256[V]) With(indices ...V) Set256[V] { return Set256[V]{s.with(indices)} }
+
+// Without returns a copy of s with the values removed.
+func (s Set256[V]) Without(indices ...V) Set256[V] { return Set256[V]{s.without(indices)} }
+
+// Intersect returns the intersection of two sets, by value.
+func (a Set256[V]) Intersect(b Set256[V]) Set256[V] { return Set256[V]{a.and(b.set)} }
+
+// Union returns the union of two sets, by value.
+func (a Set256[V]) Union(b Set256[V]) Set256[V] { return Set256[V]{a.or(b.set)} }
+
+// And implements bitset.Binary. Prefer Intersect, which doesn't allocate.
+func (a *Set256[V]) And(b *Set256[V]) *Set256[V] { r := a.Intersect(*b); return &r }
+
+// Or implements bitset.Binary. Prefer Union, which doesn't allocate.
+func (a *Set256[V]) Or(b *Set256[V]) *Set256[V] { r := a.Union(*b); return &r }
+
+// interface adherence validation
+var (
+	_ bitset.Bitset[uint] = (*Set64[uint])(nil)
+	_ bitset.Bitset[uint] = (*Set128[uint])(nil)
+	_ bitset.Bitset[uint] = (*Set256[uint])(nil)
+
+	_ bitset.Binary[uint, *Set64[uint]]  = (*Set64[uint])(nil)
+	_ bitset.Binary[uint, *Set128[uint]] = (*Set128[uint])(nil)
+	_ bitset.Binary[uint, *Set256[uint]] = (*Set256[uint])(nil)
+
+	_ bitset.Inspect[uint] = Set64[uint]{}
+	_ bitset.Inspect[uint] = Set128[uint]{}
+	_ bitset.Inspect[uint] = Set256[uint]{}
+)
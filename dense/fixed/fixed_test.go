@@ -0,0 +1,127 @@
+package fixed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+type topping uint8
+
+const (
+	LETTUCE topping = iota + 1
+	TOMATO
+	BACON
+	CHEESE
+)
+
+func TestWith(t *testing.T) {
+	var base Set64[topping]
+	classic := base.With(LETTUCE, TOMATO)
+	bacon := classic.With(BACON)
+
+	assert.Equal(t, uint(0), base.Pop())
+	assert.Equal(t, uint(2), classic.Pop())
+	assert.True(t, bacon.Get(BACON))
+	assert.False(t, classic.Get(BACON))
+
+	assert.Equal(t, classic, bacon.Without(BACON))
+	assert.NotEqual(t, classic, bacon)
+}
+
+func TestComparable(t *testing.T) {
+	prices := map[Set64[topping]]int{}
+
+	var base Set64[topping]
+	prices[base.With(LETTUCE, TOMATO)] = 5
+	prices[base.With(TOMATO, LETTUCE, BACON)] = 7
+
+	assert.Equal(t, 5, prices[base.With(TOMATO).With(LETTUCE)])
+	assert.Equal(t, 7, prices[base.With(BACON, LETTUCE, TOMATO)])
+}
+
+func TestAllocations(t *testing.T) {
+	var base Set256[uint8]
+	var s Set256[uint8]
+
+	allocs := testing.AllocsPerRun(100, func() {
+		s = base.With(1, 200, 255).Union(base.With(3)).Intersect(base.With(1, 3)).Without(1)
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, base.With(3), s)
+}
+
+func TestSizes(t *testing.T) {
+	var s64 Set64[uint]
+	var s128 Set128[uint]
+	var s256 Set256[uint]
+
+	assert.Equal(t, 64, s64.Len())
+	assert.Equal(t, 128, s128.Cap())
+	assert.Equal(t, 256, s256.Len())
+
+	assert.Panics(t, func() { s64.Set(64) })
+	assert.NotPanics(t, func() { s64.Unset(64) })
+	assert.False(t, s128.Get(1000))
+}
+
+func TestSetUnset(t *testing.T) {
+	var s Set128[uint]
+
+	s.Set(0, 63, 64, 127)
+	s.Unset(63)
+	assert.Equal(t, []uint{0, 64, 127}, iterable.Values[uint](s))
+	assert.Equal(t, []uint{127, 64, 0}, iterable.Values(iterable.Reverse[uint](s)))
+
+	s.Clear()
+	assert.Equal(t, uint(0), s.Pop())
+}
+
+func TestLogical(t *testing.T) {
+	a, b := &Set256[uint]{}, &Set256[uint]{}
+	a.Set(1, 3, 5, 6, 7, 200)
+	b.Set(0, 2, 4, 6, 7, 200)
+
+	assert.Equal(t, []uint{6, 7, 200}, iterable.Values[uint](a.And(b)))
+	assert.Equal(t, []uint{0, 1, 2, 3, 4, 5, 6, 7, 200}, iterable.Values[uint](a.Or(b)))
+}
+
+func TestUpdateCapacity(t *testing.T) {
+	var s Set64[uint]
+	s.Set(2, 10)
+
+	assert.Panics(t, func() {
+		_ = s.Update(func(tx bitset.Tx[uint]) error {
+			tx.Set(4, 64)
+			return nil
+		})
+	})
+	assert.Equal(t, []uint{2, 10}, iterable.Values[uint](&s), "unchanged")
+
+	var wide Set64[uint32]
+	wide.Set(3, 63)
+	assert.NoError(t, wide.Update(func(tx bitset.Tx[uint32]) error {
+		tx.UnsetRange(0, ^uint32(0))
+		return nil
+	}))
+	assert.Equal(t, uint(0), wide.Pop())
+}
+
+func TestRanges(t *testing.T) {
+	var s Set256[uint]
+	s.SetRange(60, 130)
+	assert.Equal(t, uint(71), s.Pop())
+	assert.True(t, s.Get(60) && s.Get(130))
+	assert.False(t, s.Get(59) || s.Get(131))
+
+	s.UnsetRange(62, 1000)
+	assert.Equal(t, []uint{60, 61}, iterable.Values[uint](&s))
+	s.UnsetRange(300, ^uint(0))
+	assert.Equal(t, uint(2), s.Pop())
+
+	s.SetRange(0, 255)
+	assert.Equal(t, uint(256), s.Pop())
+	assert.Panics(t, func() { s.SetRange(200, 256) })
+}
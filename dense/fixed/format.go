@@ -0,0 +1,18 @@
+package fixed
+
+import (
+	"fmt"
+
+	"github.com/zblach/go-bitset"
+)
+
+// String implements fmt.Stringer, e.g. {1, 3, 5-9}. It's %v, so the members beyond those printed are counted from Pop.
+func (s set[V, A]) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. See bitset.Format for the supported verbs.
+func (s set[V, A]) Format(f fmt.State, verb rune) {
+	it, _ := s.Iterate()
+	bitset.Format[V](f, verb, it, s)
+}
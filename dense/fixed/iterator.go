@@ -0,0 +1,61 @@
+package fixed
+
+import (
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Iterate implements iterable.Iterable. The iterator works from its own copy of the set.
+func (s set[V, A]) Iterate() (iterable.Iter[V], uint) {
+	return &iterator[V, A]{set: s}, s.Pop()
+}
+
+// IterateReverse implements iterable.Reversible
+func (s set[V, A]) IterateReverse() (iterable.Iter[V], uint) {
+	return &reverseIterator[V, A]{set: s, word: len(s.w) - 1}, s.Pop()
+}
+
+// iterator consumes its copy of the set, lowest bit first.
+type iterator[V bitset.Value, A words] struct {
+	set[V, A]
+	word int
+}
+
+func (it *iterator[V, A]) Next() (V, bool) {
+	for ; it.word < len(it.w); it.word++ {
+		if w := it.w[it.word]; w != 0 {
+			bit := mb.TrailingZeros64(w)
+			it.w[it.word] &^= 1 << bit
+			return V(it.word*wordBits + bit), true
+		}
+	}
+	return 0, false
+}
+
+// reverseIterator consumes its copy of the set, highest bit first.
+type reverseIterator[V bitset.Value, A words] struct {
+	set[V, A]
+	word int
+}
+
+func (it *reverseIterator[V, A]) Next() (V, bool) {
+	for ; it.word >= 0; it.word-- {
+		if w := it.w[it.word]; w != 0 {
+			bit := 63 - mb.LeadingZeros64(w)
+			it.w[it.word] &^= 1 << bit
+			return V(it.word*wordBits + bit), true
+		}
+	}
+	return 0, false
+}
+
+var (
+	_ iterable.Iter[uint]       = (*iterator[uint, [1]uint64])(nil)
+	_ iterable.Iter[uint]       = (*reverseIterator[uint, [1]uint64])(nil)
+	_ iterable.Iterable[uint8]  = Set64[uint8]{}
+	_ iterable.Iterable[uint8]  = Set128[uint8]{}
+	_ iterable.Iterable[uint8]  = Set256[uint8]{}
+	_ iterable.Reversible[rune] = Set256[rune]{}
+)
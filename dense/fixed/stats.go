@@ -0,0 +1,18 @@
+package fixed
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Bytes implements bitset.Inspect. Fixed sets have no heap storage of their own, so it's the size of the value.
+func (s set[V, A]) Bytes() int {
+	return int(unsafe.Sizeof(s.w))
+}
+
+// Stats implements bitset.Inspect
+func (s set[V, A]) Stats() bitset.Stats[V] {
+	it, _ := s.Iterate()
+	return bitset.Measure[V](s.Bytes(), it)
+}
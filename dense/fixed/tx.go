@@ -0,0 +1,20 @@
+package fixed
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Update calls fn with a transaction, and applies its modifications if fn returns nil. Fixed sets aren't
+// threadsafe, so this is atomic only in that the set is left unchanged if fn fails, or if applying its
+// modifications panics on a value beyond the capacity.
+func (s *set[V, A]) Update(fn func(tx bitset.Tx[V]) error) error {
+	tx := bitset.NewBatch(s.Get)
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	next := *s
+	tx.Replay(next.SetRange, next.UnsetRange)
+	*s = next
+	return nil
+}
@@ -0,0 +1,342 @@
+package hier
+
+import (
+	"sync"
+
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/wait"
+	"github.com/zblach/go-bitset/mixin/logical"
+)
+
+const wordBits = 64
+
+// Bitset is a dense bitset with a hierarchy of summaries over its words, for fast navigation through large, mostly-empty regions.
+//
+// levels[0] holds the members. Each bit of levels[k] is set when the corresponding word of levels[k-1] is non-zero,
+// and levels are added until the top one is a single word. NextSet and PrevSet examine at most two words per level.
+type Bitset[V bitset.Value] struct {
+	logical.IterableMixin[V]
+
+	lock *sync.RWMutex
+
+	levels  [][]uint64
+	pop     uint
+	waiters wait.Signal // woken as members are added
+}
+
+// New instantiates a new bitset with an initial size of size.
+// This size parameter refers to the number of bitset elements, not the underlying storage.
+func New[V bitset.Value](size uint) *Bitset[V] {
+	s := &Bitset[V]{
+		lock: &sync.RWMutex{},
+	}
+	s.reset(size)
+	return s
+}
+
+// reset discards all members, and allocates enough storage for size elements.
+func (s *Bitset[V]) reset(size uint) {
+	s.levels = s.levels[:0]
+	for n := size; ; {
+		words := (n + wordBits - 1) / wordBits
+		if words == 0 {
+			words = 1
+		}
+		s.levels = append(s.levels, make([]uint64, words))
+		if words == 1 {
+			break
+		}
+		n = words
+	}
+	s.pop = 0
+}
+
+// fromWords builds a bitset, and its summaries, from its member words.
+func fromWords[V bitset.Value](words []uint64) *Bitset[V] {
+	s := New[V](uint(len(words)) * wordBits)
+	copy(s.levels[0], words)
+
+	for i, w := range words {
+		if w != 0 {
+			s.pop += uint(mb.OnesCount64(w))
+			s.mark(uint(i))
+		}
+	}
+	return s
+}
+
+// Clear unsets all elements in the bitset, and sets the internal size to zero.
+func (s *Bitset[V]) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.levels = nil
+	s.reset(0)
+}
+
+// Copy returns a deep copy of the bitset.
+func (s *Bitset[V]) Copy() *Bitset[V] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	clone := &Bitset[V]{
+		lock:   &sync.RWMutex{},
+		levels: make([][]uint64, len(s.levels)),
+		pop:    s.pop,
+	}
+	for i, level := range s.levels {
+		clone.levels[i] = make([]uint64, len(level))
+		copy(clone.levels[i], level)
+	}
+	return clone
+}
+
+// Get returns whether or not a value is set in the bitset.
+func (s *Bitset[V]) Get(index V) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.get(uint(index))
+}
+
+func (s *Bitset[V]) get(index uint) bool {
+	w := index / wordBits
+	return w < uint(len(s.levels[0])) && s.levels[0][w]&(1<<(index%wordBits)) != 0
+}
+
+// Set one or more values in the bitset.
+// The bitset, and its summaries, will be expanded if necessary.
+func (s *Bitset[V]) Set(indices ...V) {
+	if len(indices) == 0 {
+		return
+	}
+
+	maxIndex := indices[0]
+	for _, index := range indices[1:] {
+		if index > maxIndex {
+			maxIndex = index
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.growright(uint(maxIndex))
+
+	for _, index := range indices {
+		s.set(uint(index))
+	}
+}
+
+// set adds index, which must be within storage.
+func (s *Bitset[V]) set(index uint) {
+	w, bit := index/wordBits, uint64(1)<<(index%wordBits)
+	if s.levels[0][w]&bit == 0 {
+		s.levels[0][w] |= bit
+		s.pop++
+		s.mark(w)
+		s.waiters.Notify()
+	}
+}
+
+// Unset one or more values in the bitset.
+// Indices outside of range are ignored.
+func (s *Bitset[V]) Unset(indices ...V) {
+	if len(indices) == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, index := range indices {
+		s.unset(uint(index))
+	}
+}
+
+// unset removes index, if it's a member.
+func (s *Bitset[V]) unset(index uint) {
+	w, bit := index/wordBits, uint64(1)<<(index%wordBits)
+	if w >= uint(len(s.levels[0])) || s.levels[0][w]&bit == 0 {
+		return
+	}
+
+	s.levels[0][w] &^= bit
+	s.pop--
+
+	// clear summary bits for as long as words empty out
+	for k := 1; k < len(s.levels) && s.levels[k-1][w] == 0; k++ {
+		s.levels[k][w/wordBits] &^= 1 << (w % wordBits)
+		w /= wordBits
+	}
+}
+
+// mark sets the summary bits above data word w.
+func (s *Bitset[V]) mark(w uint) {
+	for k := 1; k < len(s.levels); k++ {
+		s.levels[k][w/wordBits] |= 1 << (w % wordBits)
+		w /= wordBits
+	}
+}
+
+// growright expands the underlying storage and summaries, if necessary
+func (s *Bitset[V]) growright(index uint) {
+	words := index/wordBits + 1
+	if words <= uint(len(s.levels[0])) {
+		return
+	}
+
+	for k := 0; ; k++ {
+		if k == len(s.levels) {
+			// a new top level. its only non-empty word is the previous top.
+			top := make([]uint64, 1)
+			if s.levels[k-1][0] != 0 {
+				top[0] = 1
+			}
+			s.levels = append(s.levels, top)
+		}
+		if n := uint(len(s.levels[k])); words > n {
+			s.levels[k] = append(s.levels[k], make([]uint64, words-n)...)
+		}
+		if words == 1 && k == len(s.levels)-1 {
+			return
+		}
+		words = (words + wordBits - 1) / wordBits
+	}
+}
+
+// nextSet finds the first set bit of the given level, at or after i.
+func (s *Bitset[V]) nextSet(level int, i uint) (uint, bool) {
+	lv := s.levels[level]
+	if i/wordBits >= uint(len(lv)) {
+		return 0, false
+	}
+	if word := lv[i/wordBits] >> (i % wordBits); word != 0 {
+		return i + uint(mb.TrailingZeros64(word)), true
+	}
+	if level+1 == len(s.levels) {
+		return 0, false
+	}
+
+	j, ok := s.nextSet(level+1, i/wordBits+1)
+	if !ok {
+		return 0, false
+	}
+	return j*wordBits + uint(mb.TrailingZeros64(lv[j])), true
+}
+
+// prevSet finds the last set bit of the given level, at or before i.
+func (s *Bitset[V]) prevSet(level int, i uint) (uint, bool) {
+	lv := s.levels[level]
+	if i/wordBits >= uint(len(lv)) {
+		i = uint(len(lv))*wordBits - 1
+	}
+
+	mask := uint64(1)<<(i%wordBits+1) - 1 // bits at or below i. shifting by 64 yields 0, so this saturates.
+	if word := lv[i/wordBits] & mask; word != 0 {
+		return i/wordBits*wordBits + 63 - uint(mb.LeadingZeros64(word)), true
+	}
+	if level+1 == len(s.levels) || i/wordBits == 0 {
+		return 0, false
+	}
+
+	j, ok := s.prevSet(level+1, i/wordBits-1)
+	if !ok {
+		return 0, false
+	}
+	return j*wordBits + 63 - uint(mb.LeadingZeros64(lv[j])), true
+}
+
+// NextSet returns the first member at or after from.
+func (s *Bitset[V]) NextSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.nextSet(0, uint(from))
+	return V(idx), ok
+}
+
+// PrevSet returns the last member at or before from.
+func (s *Bitset[V]) PrevSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.prevSet(0, uint(from))
+	return V(idx), ok
+}
+
+// And computes and returns the intersection of two bitsets.
+// It does not modify either bitset.
+func (a *Bitset[V]) And(b *Bitset[V]) (aAndB *Bitset[V]) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	short, long := a.levels[0], b.levels[0]
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+
+	words := make([]uint64, len(short))
+	for i, w := range short {
+		words[i] = w & long[i]
+	}
+	return fromWords[V](words)
+}
+
+// Or computes and returns the union of two bitsets.
+// It does not modify either bitset.
+func (a *Bitset[V]) Or(b *Bitset[V]) (aOrB *Bitset[V]) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	short, long := a.levels[0], b.levels[0]
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+
+	words := make([]uint64, len(long))
+	copy(words, long)
+	for i, w := range short {
+		words[i] |= w
+	}
+	return fromWords[V](words)
+}
+
+// Inspection functions
+
+// Len is the used number of bits in the member words, not including summaries.
+func (s *Bitset[V]) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.levels[0]) * wordBits
+}
+
+// Cap is the available number of bits in the member words, not including summaries.
+func (s *Bitset[V]) Cap() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return cap(s.levels[0]) * wordBits
+}
+
+// Pop is the number of bits set.
+func (s *Bitset[V]) Pop() uint {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.pop
+}
+
+// interface adherence validation
+var (
+	_ bitset.Bitset[uint]                = (*Bitset[uint])(nil)
+	_ bitset.Binary[rune, *Bitset[rune]] = (*Bitset[rune])(nil)
+	_ bitset.Inspect[uint16]             = (*Bitset[uint16])(nil)
+)
@@ -0,0 +1,97 @@
+package hier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// consistent checks that every summary bit is set exactly when the word below it is non-zero.
+func consistent[V uint | uint32](t *testing.T, s *Bitset[V]) {
+	t.Helper()
+
+	assert.Len(t, s.levels[len(s.levels)-1], 1)
+	for k := 1; k < len(s.levels); k++ {
+		assert.Equal(t, (len(s.levels[k-1])+wordBits-1)/wordBits, len(s.levels[k]), "level %d", k)
+		for w, word := range s.levels[k-1] {
+			summary := s.levels[k][w/wordBits]&(1<<(w%wordBits)) != 0
+			assert.Equal(t, word != 0, summary, "level %d, word %d", k, w)
+		}
+	}
+}
+
+func TestSizes(t *testing.T) {
+	assert.Len(t, New[uint](0).levels, 1)
+	assert.Len(t, New[uint](64).levels, 1)
+	assert.Len(t, New[uint](65).levels, 2)
+	assert.Len(t, New[uint](64*64*64+1).levels, 4)
+}
+
+func TestSetUnset(t *testing.T) {
+	s := New[uint](0)
+
+	s.Set(3, 70, 5000, 1_000_000)
+	consistent(t, s)
+	assert.Len(t, s.levels, 4)
+	assert.Equal(t, uint(4), s.Pop())
+	assert.True(t, s.Get(5000))
+	assert.False(t, s.Get(5001))
+
+	s.Unset(5000, 1_000_000, 2_000_000)
+	consistent(t, s)
+	assert.Equal(t, uint(2), s.Pop())
+	assert.Equal(t, []uint{3, 70}, iterable.Values[uint](s))
+
+	s.Clear()
+	consistent(t, s)
+	assert.Len(t, s.levels, 1)
+	assert.Equal(t, uint(0), s.Pop())
+}
+
+func TestNavigate(t *testing.T) {
+	s := New[uint32](0)
+	s.Set(7, 64*64*3+1, 64*64*64*2)
+
+	next, ok := s.NextSet(8)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(64*64*3+1), next)
+
+	next, ok = s.NextSet(64*64*3 + 2)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(64*64*64*2), next)
+
+	_, ok = s.NextSet(64*64*64*2 + 1)
+	assert.False(t, ok)
+
+	prev, ok := s.PrevSet(64*64*64*2 - 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(64*64*3+1), prev)
+
+	prev, ok = s.PrevSet(64 * 64 * 3)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(7), prev)
+
+	_, ok = s.PrevSet(6)
+	assert.False(t, ok)
+
+	assert.Equal(t, []uint32{64 * 64 * 64 * 2, 64*64*3 + 1, 7}, iterable.Values(iterable.Reverse[uint32](s)))
+}
+
+func TestLogical(t *testing.T) {
+	a := New[uint](0)
+	b := New[uint](0)
+
+	a.Set(1, 3, 5, 6, 7, 100_000)
+	b.Set(0, 2, 4, 6, 7)
+
+	aAndB := a.And(b)
+	aOrB := a.Or(b)
+	consistent(t, aAndB)
+	consistent(t, aOrB)
+
+	assert.Equal(t, []uint{6, 7}, iterable.Values[uint](aAndB))
+	assert.Equal(t, []uint{0, 1, 2, 3, 4, 5, 6, 7, 100_000}, iterable.Values[uint](aOrB))
+	assert.Equal(t, uint(9), aOrB.Pop())
+	assert.Equal(t, 64, aAndB.Len())
+}
@@ -0,0 +1,75 @@
+package hier
+
+import (
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Iterate implements iterable.Iterable
+func (s *Bitset[V]) Iterate() (iterable.Iter[V], uint) {
+	it := &Iterator[V]{Bitset: *s.Copy()}
+	return it, it.pop
+}
+
+// Iterator jumps from member to member using the summaries.
+type Iterator[V bitset.Value] struct {
+	Bitset[V]
+
+	pos  uint
+	done bool
+}
+
+func (it *Iterator[V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.done {
+		return 0, false
+	}
+
+	idx, ok := it.nextSet(0, it.pos)
+	if !ok || idx+1 == 0 {
+		it.done = true
+	}
+	it.pos = idx + 1
+	return V(idx), ok
+}
+
+// IterateReverse implements iterable.Reversible
+func (s *Bitset[V]) IterateReverse() (iterable.Iter[V], uint) {
+	it := &ReverseIterator[V]{Bitset: *s.Copy()}
+	it.end = uint(len(it.levels[0]))*wordBits - 1
+	return it, it.pop
+}
+
+// ReverseIterator enumerates a bitset in descending order.
+type ReverseIterator[V bitset.Value] struct {
+	Bitset[V]
+
+	end  uint // inclusive upper bound of the next value
+	done bool
+}
+
+func (it *ReverseIterator[V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.done {
+		return 0, false
+	}
+
+	idx, ok := it.prevSet(0, it.end)
+	if !ok || idx == 0 {
+		it.done = true
+	} else {
+		it.end = idx - 1
+	}
+	return V(idx), ok
+}
+
+var (
+	_ iterable.Iter[uint]       = (*Iterator[uint])(nil)
+	_ iterable.Iter[uint]       = (*ReverseIterator[uint])(nil)
+	_ iterable.Iterable[rune]   = (*Bitset[rune])(nil)
+	_ iterable.Reversible[rune] = (*Bitset[rune])(nil)
+)
@@ -0,0 +1,35 @@
+package hier
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Bytes implements bitset.Inspect: the bitset, its lock, and every level of words up to their capacity.
+func (s *Bitset[V]) Bytes() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	bytes := int(unsafe.Sizeof(*s)+unsafe.Sizeof(*s.lock)) + cap(s.levels)*int(unsafe.Sizeof(s.levels[0]))
+	for _, level := range s.levels {
+		bytes += cap(level) * 8
+	}
+	return bytes
+}
+
+// Stats implements bitset.Inspect. Runs are found a word at a time.
+func (s *Bitset[V]) Stats() bitset.Stats[V] {
+	bytes := s.Bytes()
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return bitset.MeasureWords[V](bytes, func(yield func(i, word uint64)) {
+		for i, w := range s.levels[0] {
+			if w != 0 {
+				yield(uint64(i), w)
+			}
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package hier
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Update calls fn with a transaction, and applies its modifications atomically if fn returns nil. See bitset.Tx.
+func (s *Bitset[V]) Update(fn func(tx bitset.Tx[V]) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tx := bitset.NewBatch(func(v V) bool { return s.get(uint(v)) })
+	if err := fn(tx); err != nil {
+		return err
+	}
+	tx.Replay(
+		func(lo, hi V) {
+			s.growright(uint(hi))
+			for v := uint(lo); ; v++ {
+				s.set(v)
+				if v == uint(hi) {
+					break
+				}
+			}
+		},
+		func(lo, hi V) {
+			for v := uint(lo); v < uint(len(s.levels[0]))*wordBits; v++ {
+				s.unset(v)
+				if v == uint(hi) {
+					break
+				}
+			}
+		},
+	)
+	return nil
+}
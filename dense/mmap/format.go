@@ -0,0 +1,17 @@
+package mmap
+
+import (
+	"fmt"
+
+	"github.com/zblach/go-bitset"
+)
+
+// String implements fmt.Stringer, e.g. {1, 3, 5-9}.
+func (s *Bitset[V]) String() string {
+	return bitset.StringRuns(s.runs())
+}
+
+// Format implements fmt.Formatter. See bitset.Format for the supported verbs. Members are read a run at a time.
+func (s *Bitset[V]) Format(f fmt.State, verb rune) {
+	bitset.FormatRuns[V](f, verb, s.runs(), s)
+}
@@ -0,0 +1,378 @@
+// Package mmap is a read-only view of a dense bitset stored in a file, which is mapped into memory rather than read.
+//
+// The file is in the codec package's binary format, with the words layout. Its words are used in place, so they must
+// be in the host's byte order, which is checked against the header when it's opened; WriteFile produces such a file.
+// Only the pages which are touched are read from disk, and they're shared with every other process mapping the file.
+//
+// The file must not be modified while it's open. Results which outlive the view, like those of And and Or, are
+// copied to the heap.
+package mmap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"unsafe"
+
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/codec"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+var (
+	ErrLayout    = errors.New("mmap: not a words-layout set")
+	ErrByteOrder = errors.New("mmap: words aren't in the host's byte order")
+)
+
+const wordBits = 64
+
+// hostBigEndian is whether the host stores words most significant byte first.
+var hostBigEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 0
+}()
+
+// Bitset is a read-only view of a file's words. It's empty once closed.
+type Bitset[V bitset.Value] struct {
+	lock *sync.RWMutex
+
+	data  []byte   // the whole file, as mapped
+	words []uint64 // within data
+	pop   uint
+}
+
+// Open maps the file at path, and validates its header. The payload isn't examined, beyond checking that its highest
+// member fits in V.
+func Open[V bitset.Value](path string) (*Bitset[V], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size < codec.HeaderSize {
+		return nil, fmt.Errorf("mmap: %w: not an encoded set", codec.ErrMalformed)
+	}
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("mmap: %d bytes can't be mapped", size)
+	}
+
+	data, err := mapFile(f, int(size))
+	if err != nil {
+		return nil, err
+	}
+	s, err := view[V](data)
+	if err != nil {
+		unmap(data)
+		return nil, err
+	}
+	return s, nil
+}
+
+// view validates data, and returns it as a bitset.
+func view[V bitset.Value](data []byte) (*Bitset[V], error) {
+	h, err := codec.ParseHeader(data)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("mmap: %w", err)
+	case h.Kind != codec.Words:
+		return nil, ErrLayout
+	case h.BigEndian != hostBigEndian:
+		return nil, ErrByteOrder
+	case h.Size() != uint64(len(data)):
+		return nil, fmt.Errorf("mmap: %w: payload is %d bytes, not %d", codec.ErrMalformed, len(data)-codec.HeaderSize, h.Count*8)
+	}
+
+	s := &Bitset[V]{
+		lock: &sync.RWMutex{},
+		data: data,
+		pop:  uint(h.Pop),
+	}
+	if h.Count > 0 {
+		// the header's size keeps the payload 8-byte aligned, as mappings are page-aligned
+		s.words = unsafe.Slice((*uint64)(unsafe.Pointer(&data[codec.HeaderSize])), h.Count)
+	}
+
+	if top, ok := s.prevSet(^uint(0)); ok && uint(V(top)) != top {
+		return nil, fmt.Errorf("mmap: %w: %d is out of range", codec.ErrMalformed, top)
+	}
+	return s, nil
+}
+
+// Close unmaps the file. The view is empty from then on.
+func (s *Bitset[V]) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.data == nil {
+		return nil
+	}
+	err := unmap(s.data)
+	s.data, s.words, s.pop = nil, nil, 0
+	return err
+}
+
+// Get returns whether index is a member.
+func (s *Bitset[V]) Get(index V) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	elem := uint(index) / wordBits
+	return elem < uint(len(s.words)) && s.words[elem]&(1<<(uint(index)%wordBits)) != 0
+}
+
+// Pop is the number of members, as recorded in the header.
+func (s *Bitset[V]) Pop() uint {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.pop
+}
+
+// Len is the number of bits in the file's payload.
+func (s *Bitset[V]) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.words) * wordBits
+}
+
+// Cap is the same as Len, as the view can't grow.
+func (s *Bitset[V]) Cap() int {
+	return s.Len()
+}
+
+// Iterate implements iterable.Iterable. Words are read as they're reached, rather than copied up front.
+func (s *Bitset[V]) Iterate() (iterable.Iter[V], uint) {
+	return &iterator[V]{s: s}, s.Pop()
+}
+
+type iterator[V bitset.Value] struct {
+	s    *Bitset[V]
+	elem uint   // of the next word to load
+	word uint64 // members of word elem-1 which haven't been returned
+}
+
+func (it *iterator[V]) Next() (V, bool) {
+	if it.word == 0 && !it.load() {
+		return 0, false
+	}
+	bit := uint(mb.TrailingZeros64(it.word))
+	it.word &= it.word - 1
+	return V((it.elem-1)*wordBits + bit), true
+}
+
+// load finds the next non-empty word.
+func (it *iterator[V]) load() bool {
+	it.s.lock.RLock()
+	defer it.s.lock.RUnlock()
+
+	for ; it.elem < uint(len(it.s.words)); it.elem++ {
+		if w := it.s.words[it.elem]; w != 0 {
+			it.word = w
+			it.elem++
+			return true
+		}
+	}
+	return false
+}
+
+// NextSet returns the first member at or after from. Empty words are skipped over.
+func (s *Bitset[V]) NextSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.nextSet(uint(from))
+	return V(idx), ok
+}
+
+// PrevSet returns the last member at or before from. Empty words are skipped over.
+func (s *Bitset[V]) PrevSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.prevSet(uint(from))
+	return V(idx), ok
+}
+
+// NextClear returns the first unset position at or after from. Full words are skipped over.
+// It's only false if every position from there up to the largest V is set.
+func (s *Bitset[V]) NextClear(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx := s.nextClear(uint(from))
+	return V(idx), uint(V(idx)) == idx && idx >= uint(from)
+}
+
+func (s *Bitset[V]) nextSet(from uint) (uint, bool) {
+	elem := from / wordBits
+	if elem >= uint(len(s.words)) {
+		return 0, false
+	}
+
+	if word := s.words[elem] >> (from % wordBits); word != 0 {
+		return from + uint(mb.TrailingZeros64(word)), true
+	}
+	for elem++; elem < uint(len(s.words)); elem++ {
+		if s.words[elem] != 0 {
+			return elem*wordBits + uint(mb.TrailingZeros64(s.words[elem])), true
+		}
+	}
+	return 0, false
+}
+
+func (s *Bitset[V]) prevSet(from uint) (uint, bool) {
+	if len(s.words) == 0 {
+		return 0, false
+	}
+
+	elem := from / wordBits
+	if elem >= uint(len(s.words)) {
+		elem = uint(len(s.words)) - 1
+		from = elem*wordBits + wordBits - 1
+	}
+
+	mask := uint64(1)<<(from%wordBits+1) - 1 // bits at or below from. shifting by 64 yields 0, so this saturates.
+	if word := s.words[elem] & mask; word != 0 {
+		return elem*wordBits + 63 - uint(mb.LeadingZeros64(word)), true
+	}
+	for elem > 0 {
+		elem--
+		if s.words[elem] != 0 {
+			return elem*wordBits + 63 - uint(mb.LeadingZeros64(s.words[elem])), true
+		}
+	}
+	return 0, false
+}
+
+// nextClear finds the first unset bit at or after from. Everything past the end of the payload is unset.
+func (s *Bitset[V]) nextClear(from uint) uint {
+	elem := from / wordBits
+	if elem >= uint(len(s.words)) {
+		return from
+	}
+
+	if word := ^s.words[elem] >> (from % wordBits); word != 0 {
+		return from + uint(mb.TrailingZeros64(word))
+	}
+	for elem++; elem < uint(len(s.words)); elem++ {
+		if ^s.words[elem] != 0 {
+			return elem*wordBits + uint(mb.TrailingZeros64(^s.words[elem]))
+		}
+	}
+	return uint(len(s.words)) * wordBits
+}
+
+// And computes the intersection of two views, into a new bitset on the heap.
+func (a *Bitset[V]) And(b *Bitset[V]) *bits.Bitset[uint64, V] {
+	unlock := rlockBoth(a, b)
+	defer unlock()
+
+	short, long := a.words, b.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+
+	words := make([]uint64, len(short))
+	for i, w := range short {
+		words[i] = w & long[i]
+	}
+	return bits.FromWords[uint64, V](words)
+}
+
+// Or computes the union of two views, into a new bitset on the heap.
+func (a *Bitset[V]) Or(b *Bitset[V]) *bits.Bitset[uint64, V] {
+	unlock := rlockBoth(a, b)
+	defer unlock()
+
+	short, long := a.words, b.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+
+	words := make([]uint64, len(long))
+	copy(words, long)
+	for i, w := range short {
+		words[i] |= w
+	}
+	return bits.FromWords[uint64, V](words)
+}
+
+// rlockBoth read-locks a and b, only once if they're the same view.
+func rlockBoth[V bitset.Value](a, b *Bitset[V]) (unlock func()) {
+	a.lock.RLock()
+	if a == b {
+		return a.lock.RUnlock
+	}
+	b.lock.RLock()
+	return func() {
+		b.lock.RUnlock()
+		a.lock.RUnlock()
+	}
+}
+
+// WriteFile writes the members of s to path, in the words layout and the host's byte order, so that it can be opened.
+// Words are streamed to the file as they're filled, and the header is written last, once the population is known.
+func WriteFile[V bitset.Value](path string, s iterable.Iterable[V]) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if hostBigEndian {
+		order = binary.BigEndian
+	}
+
+	// write errors are kept by w, and returned by Flush
+	w := bufio.NewWriter(f)
+	var b [8]byte
+	put := func(word uint64) {
+		order.PutUint64(b[:], word)
+		w.Write(b[:])
+	}
+
+	w.Write(make([]byte, codec.HeaderSize)) // a placeholder, until the counts are known
+	var written, pop uint64                 // words written, and members
+	var word uint64                         // the word after those written, being filled
+	it, _ := s.Iterate()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		for elem := uint64(v) / wordBits; written < elem; written++ {
+			put(word)
+			word = 0
+		}
+		word |= 1 << (uint64(v) % wordBits)
+		pop++
+	}
+	if pop > 0 {
+		put(word)
+		written++
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	h := codec.Header{Kind: codec.Words, BigEndian: hostBigEndian, Pop: pop, Count: written}
+	if _, err := f.WriteAt(codec.AppendHeader(nil, h), 0); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+var (
+	_ bitset.Inspect[uint]    = (*Bitset[uint])(nil)
+	_ iterable.Iterable[uint] = (*Bitset[uint])(nil)
+)
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package mmap
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the first size bytes of f onto the heap, where mapping isn't supported.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	// a fresh allocation this large is 8-byte aligned, as the words require
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func unmap([]byte) error {
+	return nil
+}
@@ -0,0 +1,196 @@
+package mmap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/codec"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/iterable"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+func write(t *testing.T, vals ...uint) string {
+	src := bits.New[uint64, uint](0)
+	src.Set(vals...)
+
+	path := filepath.Join(t.TempDir(), "set")
+	assert.NoError(t, WriteFile[uint](path, src))
+	return path
+}
+
+func TestOpen(t *testing.T) {
+	s, err := Open[uint](write(t, 1, 2, 63, 64, 1000, 4095))
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint(6), s.Pop())
+	assert.Equal(t, 4096, s.Len())
+	assert.True(t, s.Get(64))
+	assert.False(t, s.Get(65))
+	assert.False(t, s.Get(1<<20))
+	assert.Equal(t, []uint{1, 2, 63, 64, 1000, 4095}, iterable.Values[uint](s))
+
+	v, ok := s.NextSet(65)
+	assert.Equal(t, uint(1000), v)
+	assert.True(t, ok)
+	_, ok = s.NextSet(4096)
+	assert.False(t, ok)
+	v, ok = s.PrevSet(999)
+	assert.Equal(t, uint(64), v)
+	assert.True(t, ok)
+	v, ok = s.PrevSet(1 << 20)
+	assert.Equal(t, uint(4095), v)
+	assert.True(t, ok)
+	v, ok = s.NextClear(1)
+	assert.Equal(t, uint(3), v)
+	assert.True(t, ok)
+
+	assert.NoError(t, s.Close())
+	assert.NoError(t, s.Close())
+	assert.False(t, s.Get(64))
+	assert.Equal(t, uint(0), s.Pop())
+	assert.Empty(t, iterable.Values[uint](s))
+}
+
+func TestOpenEmpty(t *testing.T) {
+	s, err := Open[uint](write(t))
+	assert.NoError(t, err)
+	defer s.Close()
+
+	assert.Equal(t, uint(0), s.Pop())
+	assert.Equal(t, 0, s.Len())
+	assert.False(t, s.Get(0))
+	_, ok := s.PrevSet(10)
+	assert.False(t, ok)
+}
+
+func TestAndOr(t *testing.T) {
+	a, err := Open[uint](write(t, 1, 100, 200, 5000))
+	assert.NoError(t, err)
+	defer a.Close()
+	b, err := Open[uint](write(t, 1, 2, 200))
+	assert.NoError(t, err)
+	defer b.Close()
+
+	and := a.And(b)
+	assert.Equal(t, []uint{1, 200}, iterable.Values[uint](and))
+	assert.Equal(t, uint(2), and.Pop())
+
+	or := b.Or(a)
+	assert.Equal(t, []uint{1, 2, 100, 200, 5000}, iterable.Values[uint](or))
+	assert.Equal(t, uint(5), or.Pop())
+
+	// the results are independent of the views
+	or.Set(7)
+	assert.False(t, a.Get(7))
+	assert.NoError(t, a.Close())
+	assert.Equal(t, []uint{1, 200}, iterable.Values[uint](and))
+
+	assert.Equal(t, []uint{1, 2, 200}, iterable.Values[uint](b.And(b)))
+}
+
+func TestIterateLazily(t *testing.T) {
+	s, err := Open[uint](write(t, 3, 700))
+	assert.NoError(t, err)
+
+	it, size := s.Iterate()
+	assert.Equal(t, uint(2), size)
+	v, ok := it.Next()
+	assert.Equal(t, uint(3), v)
+	assert.True(t, ok)
+
+	// closing part way through ends the iteration, rather than faulting
+	assert.NoError(t, s.Close())
+	_, ok = it.Next()
+	assert.False(t, ok)
+}
+
+func TestMalformed(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "set")
+	good, err := os.ReadFile(write(t, 1, 2, 300))
+	assert.NoError(t, err)
+
+	open := func(data []byte) error {
+		assert.NoError(t, os.WriteFile(path, data, 0o644))
+		s, err := Open[uint](path)
+		if err == nil {
+			s.Close()
+		}
+		return err
+	}
+
+	assert.NoError(t, open(good))
+
+	for _, n := range []int{0, 10, codec.HeaderSize, len(good) - 1} {
+		assert.ErrorIs(t, open(good[:n]), codec.ErrMalformed, n)
+	}
+	assert.ErrorIs(t, open(append(good[:len(good):len(good)], 0)), codec.ErrMalformed)
+
+	// words in the other byte order
+	h, err := codec.ParseHeader(good)
+	assert.NoError(t, err)
+	h.BigEndian = !h.BigEndian
+	swapped := append(codec.AppendHeader(nil, h), good[codec.HeaderSize:]...)
+	assert.ErrorIs(t, open(swapped), ErrByteOrder)
+
+	// a sparse set, which is encoded as ranges
+	src := bits.New[uint64, uint](0)
+	src.Set(1 << 20)
+	assert.ErrorIs(t, open(codec.MarshalBinary[uint](src)), ErrLayout)
+
+	// a member which doesn't fit
+	assert.NoError(t, os.WriteFile(path, good, 0o644))
+	_, err = Open[uint8](path)
+	assert.ErrorIs(t, err, codec.ErrMalformed)
+
+	_, err = Open[uint](filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestInspect(t *testing.T) {
+	s, err := Open[uint](write(t, 1, 3, 5, 6, 7, 8, 9, 200))
+	assert.NoError(t, err)
+	defer s.Close()
+
+	assert.Equal(t, "{1, 3, 5-9, 200}", s.String())
+	assert.Equal(t, "{1, 3, … +6 more}", fmt.Sprintf("%.2v", s))
+	assert.Equal(t, "{1, 3, 5-9, 200} (pop 8, len 256, cap 256)", fmt.Sprintf("%+v", s))
+
+	st := s.Stats()
+	assert.Equal(t, bitset.Stats[uint]{
+		Pop:           8,
+		Bytes:         s.Bytes(),
+		Min:           1,
+		Max:           200,
+		Runs:          4,
+		Words:         2,
+		Density:       8.0 / 200,
+		Fragmentation: 3.0 / 7,
+	}, st)
+	assert.Greater(t, st.Bytes, codec.HeaderSize+4*8)
+}
+
+func TestWriteFileStreams(t *testing.T) {
+	// a million words, which are written as they're filled rather than built up first
+	src := rangeset.New[uint]()
+	src.Set(5, 1<<26)
+	path := filepath.Join(t.TempDir(), "set")
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	assert.NoError(t, WriteFile[uint](path, src))
+	runtime.ReadMemStats(&after)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+
+	s, err := Open[uint](path)
+	assert.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, []uint{5, 1 << 26}, iterable.Values[uint](s))
+	assert.Equal(t, 1<<26+64, s.Len())
+}
@@ -0,0 +1,37 @@
+package mmap
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Bytes implements bitset.Inspect: the view, its lock, and the mapped file, whose pages are shared rather than
+// allocated.
+func (s *Bitset[V]) Bytes() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return int(unsafe.Sizeof(*s)+unsafe.Sizeof(*s.lock)) + len(s.data)
+}
+
+// Stats implements bitset.Inspect. Runs are found a word at a time.
+func (s *Bitset[V]) Stats() bitset.Stats[V] {
+	return bitset.MeasureRuns(s.Bytes(), s.runs())
+}
+
+// runs returns the runs of members, in the form bitset's run functions take. Empty and full words are skipped over.
+func (s *Bitset[V]) runs() func() (V, V, bool) {
+	pos := uint(0)
+	return func() (V, V, bool) {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+
+		start, ok := s.nextSet(pos)
+		if !ok {
+			return 0, 0, false
+		}
+		pos = s.nextClear(start)
+		return V(start), V(pos - 1), true
+	}
+}
@@ -0,0 +1,78 @@
+package window
+
+import (
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Iterate implements iterable.Iterable
+func (s *Bitset[V]) Iterate() (iterable.Iter[V], uint) {
+	it := &Iterator[V]{Bitset: *s.Copy()}
+	it.pos = it.base * wordBits
+	return it, it.pop
+}
+
+// Iterator skips from member to member, over a copy of the window.
+type Iterator[V bitset.Value] struct {
+	Bitset[V]
+
+	pos  uint
+	done bool
+}
+
+func (it *Iterator[V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.done {
+		return 0, false
+	}
+
+	idx, ok := it.nextSet(it.pos)
+	if !ok || V(idx+1) < V(idx) {
+		// end of the window, or of the range of V
+		it.done = true
+	}
+	it.pos = idx + 1
+	return V(idx), ok
+}
+
+// IterateReverse implements iterable.Reversible
+func (s *Bitset[V]) IterateReverse() (iterable.Iter[V], uint) {
+	it := &ReverseIterator[V]{Bitset: *s.Copy()}
+	it.end = (it.base+uint(len(it.words)))*wordBits - 1
+	it.done = len(it.words) == 0
+	return it, it.pop
+}
+
+// ReverseIterator enumerates a bitset in descending order.
+type ReverseIterator[V bitset.Value] struct {
+	Bitset[V]
+
+	end  uint // inclusive upper bound of the next value
+	done bool
+}
+
+func (it *ReverseIterator[V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.done {
+		return 0, false
+	}
+
+	idx, ok := it.prevSet(it.end)
+	if !ok || idx == 0 {
+		it.done = true
+	} else {
+		it.end = idx - 1
+	}
+	return V(idx), ok
+}
+
+var (
+	_ iterable.Iter[uint]       = (*Iterator[uint])(nil)
+	_ iterable.Iter[uint]       = (*ReverseIterator[uint])(nil)
+	_ iterable.Iterable[rune]   = (*Bitset[rune])(nil)
+	_ iterable.Reversible[rune] = (*Bitset[rune])(nil)
+)
@@ -0,0 +1,69 @@
+package window
+
+import (
+	mb "math/bits"
+)
+
+// NextSet returns the first member at or after from. Empty words are skipped over.
+func (s *Bitset[V]) NextSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.nextSet(uint(from))
+	return V(idx), ok
+}
+
+// PrevSet returns the last member at or before from. Empty words are skipped over.
+func (s *Bitset[V]) PrevSet(from V) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	idx, ok := s.prevSet(uint(from))
+	return V(idx), ok
+}
+
+// nextSet finds the first set bit at or after from, skipping over empty words.
+func (s *Bitset[V]) nextSet(from uint) (uint, bool) {
+	if from/wordBits < s.base {
+		from = s.base * wordBits
+	}
+	i := from/wordBits - s.base
+	if i >= uint(len(s.words)) {
+		return 0, false
+	}
+
+	if word := s.words[i] >> (from % wordBits); word != 0 {
+		return from + uint(mb.TrailingZeros64(word)), true
+	}
+	for i++; i < uint(len(s.words)); i++ {
+		if s.words[i] != 0 {
+			return (s.base+i)*wordBits + uint(mb.TrailingZeros64(s.words[i])), true
+		}
+	}
+	return 0, false
+}
+
+// prevSet finds the last set bit at or before from, skipping over empty words.
+func (s *Bitset[V]) prevSet(from uint) (uint, bool) {
+	if len(s.words) == 0 || from/wordBits < s.base {
+		return 0, false
+	}
+
+	i := from/wordBits - s.base
+	if i >= uint(len(s.words)) {
+		i = uint(len(s.words)) - 1
+		from = (s.base+i)*wordBits + wordBits - 1
+	}
+
+	mask := uint64(1)<<(from%wordBits+1) - 1 // bits at or below from. shifting by 64 yields 0, so this saturates.
+	if word := s.words[i] & mask; word != 0 {
+		return (s.base+i)*wordBits + 63 - uint(mb.LeadingZeros64(word)), true
+	}
+	for i > 0 {
+		i--
+		if s.words[i] != 0 {
+			return (s.base+i)*wordBits + 63 - uint(mb.LeadingZeros64(s.words[i])), true
+		}
+	}
+	return 0, false
+}
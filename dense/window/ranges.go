@@ -0,0 +1,82 @@
+package window
+
+import (
+	mb "math/bits"
+)
+
+// SetRange sets every value in [lo, hi], a word at a time.
+// The window will be expanded, in either direction, if necessary.
+func (s *Bitset[V]) SetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.setRange(lo, hi)
+}
+
+// UnsetRange unsets every value in [lo, hi], a word at a time.
+// Values outside of the window are ignored, and the window shrinks if its edges empty out.
+func (s *Bitset[V]) UnsetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.unsetRange(lo, hi)
+}
+
+// fill sets or clears [lo, hi], which must be within the window.
+func (s *Bitset[V]) fill(lo, hi uint, set bool) {
+	pop := s.pop
+	for w := lo / wordBits; w <= hi/wordBits; w++ {
+		mask := ^uint64(0)
+		if w == lo/wordBits {
+			mask &= ^uint64(0) << (lo % wordBits)
+		}
+		if w == hi/wordBits {
+			mask &= ^uint64(0) >> (wordBits - 1 - hi%wordBits)
+		}
+
+		word := &s.words[w-s.base]
+		if set {
+			s.pop += uint(mb.OnesCount64(mask &^ *word))
+			*word |= mask
+		} else {
+			s.pop -= uint(mb.OnesCount64(mask & *word))
+			*word &^= mask
+		}
+	}
+	if s.pop != pop {
+		s.version++
+	}
+}
+
+func (s *Bitset[V]) setRange(lo, hi V) {
+	s.fit(uint(lo)/wordBits, uint(hi)/wordBits)
+	s.fill(uint(lo), uint(hi), true)
+	s.waiters.Notify()
+}
+
+func (s *Bitset[V]) unsetRange(lo, hi V) {
+	first, last := s.base*wordBits, (s.base+uint(len(s.words)))*wordBits-1 // inclusive, so a window ending at the largest uint doesn't overflow
+	if len(s.words) == 0 || uint(hi) < first || uint(lo) > last {
+		return
+	}
+	if uint(lo) < first {
+		lo = V(first)
+	}
+	if uint(hi) > last {
+		hi = V(last)
+	}
+
+	pop := s.pop
+	s.fill(uint(lo), uint(hi), false)
+	if s.pop != pop {
+		s.shrink()
+	}
+}
@@ -0,0 +1,21 @@
+package window
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Bytes implements bitset.Inspect: the bitset, its lock, and the window's words up to their capacity.
+func (s *Bitset[V]) Bytes() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return int(unsafe.Sizeof(*s)+unsafe.Sizeof(*s.lock)) + cap(s.words)*8
+}
+
+// Stats implements bitset.Inspect
+func (s *Bitset[V]) Stats() bitset.Stats[V] {
+	it, _ := s.Iterate()
+	return bitset.Measure[V](s.Bytes(), it)
+}
@@ -0,0 +1,321 @@
+package window
+
+import (
+	"sync"
+
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/value"
+	"github.com/zblach/go-bitset/internal/wait"
+	"github.com/zblach/go-bitset/mixin/logical"
+)
+
+const wordBits = 64
+
+// Bitset is a dense bitset which only stores the words between its smallest and largest members, for values which
+// start far from zero. Storage grows in either direction as members are added, and shrinks as the edges empty out.
+//
+// words[i] holds the values from (base+i)*64 to (base+i)*64+63. An empty bitset stores no words.
+type Bitset[V bitset.Value] struct {
+	logical.IterableMixin[V]
+
+	lock *sync.RWMutex
+
+	base    uint // word index of words[0]
+	words   []uint64
+	pop     uint
+	version uint64      // incremented on every modification
+	waiters wait.Signal // woken as members are added
+}
+
+// New instantiates a new bitset, with storage for the elements [base, base+size).
+// Like bits.New, the size is a hint; the window moves to fit whatever is set. It stops at the largest V.
+func New[V bitset.Value](base V, size uint) *Bitset[V] {
+	s := &Bitset[V]{
+		lock: &sync.RWMutex{},
+	}
+	if size > 0 {
+		hi := uint(value.Max[V]())
+		if rest := hi - uint(base); size-1 < rest {
+			hi = uint(base) + size - 1
+		}
+		s.fit(uint(base)/wordBits, hi/wordBits)
+	}
+	return s
+}
+
+// Base is the first value in the stored window. It's always a multiple of 64.
+func (s *Bitset[V]) Base() V {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return V(s.base * wordBits)
+}
+
+// Clear unsets all elements in the bitset, and releases the window.
+func (s *Bitset[V]) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.base, s.words = 0, nil
+	s.pop = 0
+	s.version++
+}
+
+// Copy returns a deep copy of the bitset.
+func (s *Bitset[V]) Copy() *Bitset[V] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	clone := &Bitset[V]{
+		lock: &sync.RWMutex{},
+		base: s.base,
+		pop:  s.pop,
+	}
+	if len(s.words) > 0 {
+		clone.words = make([]uint64, len(s.words))
+		copy(clone.words, s.words)
+	}
+	return clone
+}
+
+// Get returns whether or not a value is set in the bitset.
+// Values outside of the window are never set.
+func (s *Bitset[V]) Get(index V) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.get(uint(index))
+}
+
+// get is an unlocked Get.
+func (s *Bitset[V]) get(index uint) bool {
+	w := index / wordBits
+	return w >= s.base && w-s.base < uint(len(s.words)) && s.words[w-s.base]&(1<<(index%wordBits)) != 0
+}
+
+// Set one or more values in the bitset.
+// The window will be expanded, in either direction, if necessary.
+func (s *Bitset[V]) Set(indices ...V) {
+	if len(indices) == 0 {
+		return
+	}
+
+	lo, hi := indices[0], indices[0]
+	for _, index := range indices[1:] {
+		if index < lo {
+			lo = index
+		}
+		if index > hi {
+			hi = index
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.fit(uint(lo)/wordBits, uint(hi)/wordBits)
+
+	pop := s.pop
+	for _, index := range indices {
+		w, bit := uint(index)/wordBits-s.base, uint64(1)<<(uint(index)%wordBits)
+		if s.words[w]&bit == 0 {
+			s.words[w] |= bit
+			s.pop++
+		}
+	}
+	if s.pop != pop {
+		s.version++
+		s.waiters.Notify()
+	}
+}
+
+// Unset one or more values in the bitset.
+// Values outside of the window are ignored, and the window shrinks if its edges empty out.
+func (s *Bitset[V]) Unset(indices ...V) {
+	if len(indices) == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	pop := s.pop
+	for _, index := range indices {
+		w := uint(index) / wordBits
+		if w < s.base || w-s.base >= uint(len(s.words)) {
+			continue
+		}
+
+		if bit := uint64(1) << (uint(index) % wordBits); s.words[w-s.base]&bit != 0 {
+			s.words[w-s.base] &^= bit
+			s.pop--
+		}
+	}
+	if s.pop != pop {
+		s.shrink()
+		s.version++
+	}
+}
+
+// fit expands the window, if necessary, to include the words lo through hi.
+func (s *Bitset[V]) fit(lo, hi uint) {
+	if len(s.words) == 0 {
+		s.base, s.words = lo, make([]uint64, hi-lo+1)
+		return
+	}
+	if lo < s.base {
+		s.growleft(lo)
+	}
+	s.growright(hi)
+}
+
+// growright expands the window, if necessary, so that word w is its last.
+func (s *Bitset[V]) growright(w uint) {
+	if end := s.base + uint(len(s.words)); w >= end {
+		s.words = append(s.words, make([]uint64, w-end+1)...)
+	}
+}
+
+// growleft expands the window so that it starts at or before word w. Like append, it reserves as many words again as
+// the window already holds, up to w, so that adding values in descending order is amortised.
+func (s *Bitset[V]) growleft(w uint) {
+	n := s.base - w
+	if extra := uint(len(s.words)); extra > w {
+		n += w
+	} else {
+		n += extra
+	}
+
+	words := make([]uint64, uint(len(s.words))+n)
+	copy(words[n:], s.words)
+	s.base, s.words = s.base-n, words
+}
+
+// shrink trims empty words from both edges of the window.
+// Storage is reallocated once most of it is unused, so a window which has moved doesn't hold onto the old one.
+func (s *Bitset[V]) shrink() {
+	lo, hi := 0, len(s.words)
+	for lo < hi && s.words[lo] == 0 {
+		lo++
+	}
+	for hi > lo && s.words[hi-1] == 0 {
+		hi--
+	}
+
+	switch {
+	case lo == hi:
+		s.base, s.words = 0, nil
+	case (hi-lo)*4 <= cap(s.words):
+		words := make([]uint64, hi-lo)
+		copy(words, s.words[lo:hi])
+		s.base, s.words = s.base+uint(lo), words
+	default:
+		s.base, s.words = s.base+uint(lo), s.words[lo:hi]
+	}
+}
+
+// fromWords builds a bitset from a window of words, trimming any empty edges.
+func fromWords[V bitset.Value](base uint, words []uint64) *Bitset[V] {
+	s := &Bitset[V]{
+		lock:  &sync.RWMutex{},
+		base:  base,
+		words: words,
+	}
+	for _, w := range words {
+		s.pop += uint(mb.OnesCount64(w))
+	}
+	s.shrink()
+	return s
+}
+
+// And computes and returns the intersection of two bitsets, which needn't have the same window.
+// It does not modify either bitset.
+func (a *Bitset[V]) And(b *Bitset[V]) (aAndB *Bitset[V]) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	lo, hi := a.base, a.base+uint(len(a.words))
+	if b.base > lo {
+		lo = b.base
+	}
+	if end := b.base + uint(len(b.words)); end < hi {
+		hi = end
+	}
+	if lo >= hi {
+		return fromWords[V](0, nil)
+	}
+
+	words := make([]uint64, hi-lo)
+	for i := range words {
+		words[i] = a.words[lo-a.base+uint(i)] & b.words[lo-b.base+uint(i)]
+	}
+	return fromWords[V](lo, words)
+}
+
+// Or computes and returns the union of two bitsets, which needn't have the same window.
+// It does not modify either bitset.
+func (a *Bitset[V]) Or(b *Bitset[V]) (aOrB *Bitset[V]) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	switch {
+	case len(a.words) == 0:
+		return fromWords[V](b.base, append([]uint64(nil), b.words...))
+	case len(b.words) == 0:
+		return fromWords[V](a.base, append([]uint64(nil), a.words...))
+	}
+
+	lo, hi := a.base, a.base+uint(len(a.words))
+	if b.base < lo {
+		lo = b.base
+	}
+	if end := b.base + uint(len(b.words)); end > hi {
+		hi = end
+	}
+
+	words := make([]uint64, hi-lo)
+	copy(words[a.base-lo:], a.words)
+	for i, w := range b.words {
+		words[b.base-lo+uint(i)] |= w
+	}
+	return fromWords[V](lo, words)
+}
+
+// Inspection functions
+
+// Len is the number of values in the window, rounded up to word size.
+func (s *Bitset[V]) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.words) * wordBits
+}
+
+// Cap is the number of values the window can hold without reallocating, rounded up to word size.
+func (s *Bitset[V]) Cap() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return cap(s.words) * wordBits
+}
+
+// Pop is the number of bits set.
+func (s *Bitset[V]) Pop() uint {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.pop
+}
+
+// interface adherence validation
+var (
+	_ bitset.Bitset[uint]                = (*Bitset[uint])(nil)
+	_ bitset.Binary[rune, *Bitset[rune]] = (*Bitset[rune])(nil)
+	_ bitset.Inspect[uint16]             = (*Bitset[uint16])(nil)
+)
@@ -0,0 +1,139 @@
+package window
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+const far = 4_000_000_000
+
+func TestNew(t *testing.T) {
+	s := New[uint](far, 1000)
+	assert.Equal(t, uint(far/64*64), s.Base())
+	assert.Equal(t, 1024, s.Len())
+
+	assert.Equal(t, 0, New[uint](0, 0).Len())
+
+	// hints past the largest value stop there
+	top := New[uint64](0xffffffffffffff40, 0x106)
+	assert.Equal(t, 192, top.Len())
+	top.Set(math.MaxUint64)
+	assert.True(t, top.Get(math.MaxUint64))
+	assert.Equal(t, 256, New[uint8](10, 1000).Len())
+}
+
+func TestSetUnset(t *testing.T) {
+	s := New[uint](0, 0)
+
+	s.Set(far, far+1, far+200)
+	assert.Equal(t, uint(far/64*64), s.Base())
+	assert.Equal(t, 4*64, s.Len(), "only the words from far to far+200 are stored")
+	assert.Equal(t, uint(3), s.Pop())
+	assert.True(t, s.Get(far+200))
+	assert.False(t, s.Get(far+2))
+	assert.False(t, s.Get(3))
+
+	s.Set(far - 1000)
+	assert.LessOrEqual(t, s.Base(), uint(far-1000), "grown to the left")
+	assert.Equal(t, []uint{far - 1000, far, far + 1, far + 200}, iterable.Values[uint](s))
+
+	s.Unset(far-1000, far+200, 5)
+	assert.Equal(t, uint(far/64*64), s.Base(), "shrunk from both edges")
+	assert.Equal(t, 64, s.Len())
+	assert.Equal(t, uint(2), s.Pop())
+
+	s.Unset(far, far+1)
+	assert.Equal(t, 0, s.Len())
+	assert.Equal(t, uint(0), s.Base())
+}
+
+func TestGrowLeft_Amortized(t *testing.T) {
+	s := New[uint](0, 0)
+	grows := 0
+	for v := uint(far + 64*1000); v >= far; v -= 64 {
+		base := s.Base()
+		s.Set(v)
+		if s.Base() != base {
+			grows++
+		}
+	}
+	assert.Equal(t, uint(1001), s.Pop())
+	assert.Less(t, grows, 20)
+}
+
+func TestSetRange(t *testing.T) {
+	s := New[uint64](0, 0)
+	s.SetRange(far+10, far+300)
+	assert.Equal(t, uint(291), s.Pop())
+
+	s.UnsetRange(0, far+63)
+	s.UnsetRange(far+128, far+1000)
+	assert.Equal(t, uint64(far/64*64+64), s.Base())
+	assert.Equal(t, fmt.Sprintf("{%d-%d}", far/64*64+64, far/64*64+127), s.String())
+}
+
+func TestNavigate(t *testing.T) {
+	s := New[uint32](0, 0)
+	s.Set(far, far+100, far+5000)
+
+	next, ok := s.NextSet(0)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(far), next)
+
+	next, ok = s.NextSet(far + 101)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(far+5000), next)
+
+	_, ok = s.NextSet(far + 5001)
+	assert.False(t, ok)
+
+	prev, ok := s.PrevSet(1<<32 - 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(far+5000), prev)
+
+	_, ok = s.PrevSet(far - 1)
+	assert.False(t, ok)
+}
+
+func TestIterate(t *testing.T) {
+	s := New[uint8](0, 0)
+	s.Set(200, 255, 130)
+	assert.Equal(t, []uint8{130, 200, 255}, iterable.Values[uint8](s))
+	assert.Equal(t, []uint8{255, 200, 130}, iterable.Values[uint8](iterable.Reverse[uint8](s)))
+
+	vals, _, done := s.Page(bitset.After[uint8](130), 0)
+	assert.Equal(t, []uint8{200, 255}, vals)
+	assert.True(t, done)
+
+	assert.Empty(t, iterable.Values[uint8](New[uint8](0, 0)))
+	assert.Empty(t, iterable.Values[uint8](iterable.Reverse[uint8](New[uint8](0, 0))))
+}
+
+func TestBinary(t *testing.T) {
+	// far enough to leave most words unstored, but not so far that the union with 10 takes hundreds of megabytes
+	const mid = 3_000_000
+
+	a, b := New[uint](0, 0), New[uint](0, 0)
+	a.Set(mid, mid+64, mid+1000)
+	b.Set(10, mid+64, mid+1000, mid+100_000)
+
+	assert.Equal(t, []uint{mid + 64, mid + 1000}, iterable.Values[uint](a.And(b)))
+	assert.Equal(t, []uint{mid + 64, mid + 1000}, iterable.Values[uint](b.And(a)))
+	assert.Equal(t, uint(mid+64), a.And(b).Base(), "trimmed to the members")
+
+	or := a.Or(b)
+	assert.Equal(t, []uint{10, mid, mid + 64, mid + 1000, mid + 100_000}, iterable.Values[uint](or))
+	assert.Equal(t, uint(5), or.Pop())
+	assert.Equal(t, uint(0), or.Base())
+
+	c := New[uint](0, 0)
+	c.Set(5)
+	assert.Equal(t, 0, a.And(c).Len(), "disjoint windows")
+	assert.Equal(t, uint(4), a.Or(c).Pop())
+	assert.Equal(t, uint(3), a.Or(New[uint](0, 0)).Pop())
+}
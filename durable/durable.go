@@ -0,0 +1,346 @@
+// Package durable persists a bitset to a local directory, so that it survives restarts and crashes.
+//
+// The directory holds a snapshot, in the codec package's binary format, and a write-ahead log of the modifications
+// made since. Each modification is appended to the log before it's applied in memory, and the log is replaced by a
+// new snapshot periodically, or on request. Opening the directory loads the snapshot and replays the log.
+//
+// Replaying a modification which the snapshot already reflects doesn't change it, so a crash at any point while
+// snapshotting leaves the directory recoverable. A crash while appending may leave a partially-written record at the
+// end of the log; recovery detects it by its checksum or length, and discards it, along with anything after it.
+package durable
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/codec"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+const (
+	snapshotFile = "snapshot"
+	logFile      = "log"
+	tempSuffix   = ".tmp"
+)
+
+// ErrClosed is returned by modifications made after Close, or after a write to the log failed in a way which couldn't
+// be undone.
+var ErrClosed = errors.New("durable: closed")
+
+// Sync decides when the log is flushed to stable storage.
+type Sync int
+
+const (
+	// SyncAlways flushes every record before the modification returns, so none are lost to a crash.
+	SyncAlways Sync = iota
+	// SyncNever leaves flushing to the operating system, except when snapshotting and closing. Modifications made
+	// since the last flush may be lost to a crash of the host, though not of the process.
+	SyncNever
+)
+
+// Options configure a Bitset.
+type Options struct {
+	Sync Sync
+	// SnapshotEvery takes a snapshot after this many records have been logged. Zero disables it. Failures are
+	// reported by Err, rather than by the modification which triggered them.
+	SnapshotEvery int
+}
+
+// Bitset is a dense bitset whose modifications are logged to a directory.
+// Modifications return an error if they couldn't be logged, in which case they aren't applied.
+type Bitset[V bitset.Value] struct {
+	lock *sync.Mutex
+
+	set  *bits.Bitset[uint64, V]
+	dir  string
+	opts Options
+
+	log     *os.File // nil once closed
+	size    int64    // of the valid records in log
+	records int      // logged since the last snapshot
+	err     error    // from the last periodic snapshot, if it failed
+}
+
+// Open loads the bitset persisted in dir, creating the directory if necessary.
+func Open[V bitset.Value](dir string, opts Options) (*Bitset[V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &Bitset[V]{
+		lock: &sync.Mutex{},
+		set:  bits.New[uint64, V](0),
+		dir:  dir,
+		opts: opts,
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, snapshotFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := codec.UnmarshalBinary[V](s.set, data); err != nil {
+			return nil, fmt.Errorf("durable: snapshot: %w", err)
+		}
+	}
+
+	if s.log, err = os.OpenFile(filepath.Join(dir, logFile), os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return nil, err
+	}
+	if err := s.replay(); err != nil {
+		s.log.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay applies the log's records, and truncates it after the last valid one.
+func (s *Bitset[V]) replay() error {
+	r := bufio.NewReader(s.log)
+	for {
+		o, vals, size, err := readRecord(r)
+		if err == io.EOF || err == errTorn {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.apply(o, vals); err != nil {
+			return err
+		}
+		s.size += size
+		s.records++
+	}
+
+	if err := s.log.Truncate(s.size); err != nil {
+		return err
+	}
+	_, err := s.log.Seek(s.size, io.SeekStart)
+	return err
+}
+
+// apply makes a logged modification in memory.
+func (s *Bitset[V]) apply(o op, vals []uint64) error {
+	vs := make([]V, len(vals))
+	for i, v := range vals {
+		if vs[i] = V(v); uint64(vs[i]) != v {
+			return fmt.Errorf("durable: log: %w: %d is out of range", codec.ErrMalformed, v)
+		}
+	}
+
+	switch o {
+	case opSet:
+		s.set.Set(vs...)
+	case opUnset:
+		s.set.Unset(vs...)
+	case opSetRange:
+		s.set.SetRange(vs[0], vs[1])
+	case opUnsetRange:
+		s.set.UnsetRange(vs[0], vs[1])
+	case opClear:
+		s.set.Clear()
+	}
+	return nil
+}
+
+// write logs a modification, then applies it.
+func (s *Bitset[V]) write(o op, vs ...V) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.log == nil {
+		return ErrClosed
+	}
+
+	vals := make([]uint64, len(vs))
+	for i, v := range vs {
+		vals[i] = uint64(v)
+	}
+	record := appendRecord(nil, o, vals)
+
+	if _, err := s.log.Write(record); err != nil {
+		// drop whatever was written, so that later records aren't hidden behind a torn one
+		if s.log.Truncate(s.size) != nil {
+			s.fail()
+		} else if _, serr := s.log.Seek(s.size, io.SeekStart); serr != nil {
+			s.fail()
+		}
+		return err
+	}
+	if s.opts.Sync == SyncAlways {
+		if err := s.log.Sync(); err != nil {
+			// the record may or may not be durable, so the log can't be trusted to match memory
+			s.fail()
+			return err
+		}
+	}
+	s.size += int64(len(record))
+	s.records++
+
+	if err := s.apply(o, vals); err != nil {
+		return err
+	}
+	if s.opts.SnapshotEvery > 0 && s.records >= s.opts.SnapshotEvery {
+		// the modification is already durable, so a failure is left for Err, and retried after as many records again
+		if err := s.snapshot(); err != nil {
+			s.err, s.records = err, 0
+		}
+	}
+	return nil
+}
+
+// fail closes the log, after an error which leaves it inconsistent with memory.
+func (s *Bitset[V]) fail() {
+	s.log.Close()
+	s.log = nil
+}
+
+// Set logs and adds values.
+func (s *Bitset[V]) Set(indices ...V) error {
+	if len(indices) == 0 {
+		return nil
+	}
+	return s.write(opSet, indices...)
+}
+
+// Unset logs and removes values.
+func (s *Bitset[V]) Unset(indices ...V) error {
+	if len(indices) == 0 {
+		return nil
+	}
+	return s.write(opUnset, indices...)
+}
+
+// SetRange logs and adds every value in [lo, hi], as a single record.
+func (s *Bitset[V]) SetRange(lo, hi V) error {
+	if lo > hi {
+		return nil
+	}
+	return s.write(opSetRange, lo, hi)
+}
+
+// UnsetRange logs and removes every value in [lo, hi], as a single record.
+func (s *Bitset[V]) UnsetRange(lo, hi V) error {
+	if lo > hi {
+		return nil
+	}
+	return s.write(opUnsetRange, lo, hi)
+}
+
+// Clear logs and removes every value.
+func (s *Bitset[V]) Clear() error {
+	return s.write(opClear)
+}
+
+// Get returns whether index is a member.
+func (s *Bitset[V]) Get(index V) bool {
+	return s.set.Get(index)
+}
+
+// Pop returns the number of members.
+func (s *Bitset[V]) Pop() uint {
+	return s.set.Pop()
+}
+
+// Iterate implements iterable.Iterable
+func (s *Bitset[V]) Iterate() (iterable.Iter[V], uint) {
+	return s.set.Iterate()
+}
+
+// Snapshot writes the members to a new snapshot, and empties the log.
+func (s *Bitset[V]) Snapshot() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.log == nil {
+		return ErrClosed
+	}
+	return s.snapshot()
+}
+
+func (s *Bitset[V]) snapshot() error {
+	// the new snapshot must be durable before the log it replaces is emptied
+	path := filepath.Join(s.dir, snapshotFile)
+	if err := writeFile(path+tempSuffix, codec.MarshalBinary[V](s.set)); err != nil {
+		return err
+	}
+	if err := os.Rename(path+tempSuffix, path); err != nil {
+		return err
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	if err := s.log.Truncate(0); err != nil {
+		s.fail()
+		return err
+	}
+	if _, err := s.log.Seek(0, io.SeekStart); err != nil {
+		s.fail()
+		return err
+	}
+	s.size, s.records, s.err = 0, 0, nil
+	return s.log.Sync()
+}
+
+// Err returns the error from the last periodic snapshot, if it failed. The modification which triggered it succeeded
+// regardless, and the log keeps growing until a snapshot succeeds.
+func (s *Bitset[V]) Err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.err
+}
+
+// Close flushes the log, and releases it. The bitset can still be read.
+func (s *Bitset[V]) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.log == nil {
+		return nil
+	}
+	err := s.log.Sync()
+	if cerr := s.log.Close(); err == nil {
+		err = cerr
+	}
+	s.log = nil
+	return err
+}
+
+// writeFile writes data to path, and flushes it.
+func writeFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir flushes the directory's entries, so that a rename within it is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+var _ iterable.Iterable[uint] = (*Bitset[uint])(nil)
@@ -0,0 +1,186 @@
+package durable
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open[uint](dir, Options{})
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set(1, 2, 3))
+	assert.NoError(t, s.SetRange(100, 200))
+	assert.NoError(t, s.UnsetRange(150, 1000))
+	assert.NoError(t, s.Unset(2))
+	assert.NoError(t, s.Close())
+	assert.ErrorIs(t, s.Set(4), ErrClosed)
+
+	s, err = Open[uint](dir, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(52), s.Pop())
+	assert.True(t, s.Get(149))
+	assert.False(t, s.Get(2))
+
+	assert.NoError(t, s.Clear())
+	assert.NoError(t, s.Set(7))
+	// not closed, as if the process crashed
+	s, err = Open[uint](dir, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{7}, iterable.Values[uint](s))
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open[uint16](dir, Options{Sync: SyncNever, SnapshotEvery: 3})
+	assert.NoError(t, err)
+	for v := uint16(0); v < 10; v++ {
+		assert.NoError(t, s.Set(v*1000))
+	}
+	assert.FileExists(t, filepath.Join(dir, snapshotFile))
+	assert.Equal(t, int64(recordHeader+9), fileSize(t, filepath.Join(dir, logFile)), "one record since the last snapshot")
+	assert.NoError(t, s.Close())
+
+	s, err = Open[uint16](dir, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), s.Pop())
+	assert.True(t, s.Get(9000))
+
+	// a crash while snapshotting leaves a temporary file, or a log which the snapshot already reflects
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, snapshotFile+tempSuffix), []byte("partial"), 0o644))
+	assert.NoError(t, s.Unset(0))
+	log, err := os.ReadFile(filepath.Join(dir, logFile))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Snapshot())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, logFile), log, 0o644))
+
+	s, err = Open[uint16](dir, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), s.Pop())
+	assert.False(t, s.Get(0))
+}
+
+func TestTornWrites(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open[uint](dir, Options{})
+	assert.NoError(t, err)
+
+	// the members after each record
+	steps := []func() error{
+		func() error { return s.Set(1, 2) },
+		func() error { return s.SetRange(10, 20) },
+		func() error { return s.Unset(1) },
+		func() error { return s.Clear() },
+		func() error { return s.Set(5) },
+	}
+	var states [][]uint
+	var ends []int64
+	states = append(states, []uint{})
+	ends = append(ends, 0)
+	for _, step := range steps {
+		assert.NoError(t, step())
+		states = append(states, iterable.Values[uint](s))
+		ends = append(ends, fileSize(t, filepath.Join(dir, logFile)))
+	}
+	assert.NoError(t, s.Close())
+	full, err := os.ReadFile(filepath.Join(dir, logFile))
+	assert.NoError(t, err)
+
+	for n := int64(0); n <= int64(len(full)); n++ {
+		// the state after the last record which was completely written
+		i := len(ends) - 1
+		for ends[i] > n {
+			i--
+		}
+
+		torn := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(torn, logFile), full[:n], 0o644))
+		r, err := Open[uint](torn, Options{})
+		assert.NoError(t, err)
+		assert.Equal(t, states[i], iterable.Values[uint](r), "truncated to %d bytes", n)
+		assert.Equal(t, ends[i], fileSize(t, filepath.Join(torn, logFile)), "the torn record is discarded")
+
+		// later records aren't hidden by the torn one
+		assert.NoError(t, r.Set(999))
+		assert.NoError(t, r.Close())
+		r, err = Open[uint](torn, Options{})
+		assert.NoError(t, err)
+		assert.True(t, r.Get(999))
+		assert.NoError(t, r.Close())
+	}
+
+	// a corrupted record ends the log, as a torn one does
+	corrupt := append([]byte(nil), full...)
+	corrupt[ends[2]+recordHeader+1] ^= 0xff
+	torn := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(torn, logFile), corrupt, 0o644))
+	r, err := Open[uint](torn, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, states[2], iterable.Values[uint](r))
+}
+
+func TestTornLength(t *testing.T) {
+	// a record whose length claims nearly 4 GiB, with nothing after it
+	dir := t.TempDir()
+	record := binary.LittleEndian.AppendUint32(nil, 0)
+	record = binary.LittleEndian.AppendUint32(record, 0xfffffff9)
+	record = append(record, byte(opSet), 1, 2, 3, 4, 5, 6, 7)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, logFile), record, 0o644))
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	s, err := Open[uint](dir, Options{})
+	runtime.ReadMemStats(&after)
+	assert.NoError(t, err)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20), "the length isn't allocated up front")
+	assert.Zero(t, s.Pop())
+	assert.Equal(t, int64(0), fileSize(t, filepath.Join(dir, logFile)))
+}
+
+func TestSnapshotFailure(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open[uint](dir, Options{SnapshotEvery: 2})
+	assert.NoError(t, err)
+
+	// the temporary snapshot can't be written over a directory
+	blocker := filepath.Join(dir, snapshotFile+tempSuffix)
+	assert.NoError(t, os.Mkdir(blocker, 0o755))
+
+	assert.NoError(t, s.Set(1))
+	assert.NoError(t, s.Set(2), "logged and applied, despite the snapshot")
+	assert.Error(t, s.Err())
+	assert.True(t, s.Get(2))
+	assert.NoError(t, s.Set(3), "not retried until another two records")
+	assert.Error(t, s.Err())
+
+	assert.NoError(t, os.Remove(blocker))
+	assert.NoError(t, s.Set(4))
+	assert.NoError(t, s.Err())
+	assert.FileExists(t, filepath.Join(dir, snapshotFile))
+	assert.NoError(t, s.Close())
+
+	s, err = Open[uint](dir, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 3, 4}, iterable.Values[uint](s))
+}
+
+func TestMalformedSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, snapshotFile), []byte("BSET"), 0o644))
+	_, err := Open[uint](dir, Options{})
+	assert.Error(t, err)
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	return info.Size()
+}
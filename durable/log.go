@@ -0,0 +1,82 @@
+package durable
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// The log is a series of records, each of which is a modification:
+//
+//	offset  size  field
+//	0       4     CRC-32C of the body
+//	4       4     length of the body
+//	8             body; the op, followed by its 64-bit values
+//
+// Every field is little-endian. A record which is truncated, or whose checksum doesn't match, is a torn write, and
+// ends the log.
+const recordHeader = 8
+
+// op is the kind of a record.
+type op byte
+
+const (
+	opSet op = iota + 1
+	opUnset
+	opSetRange   // lo, hi
+	opUnsetRange // lo, hi
+	opClear      // no values
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// errTorn is returned by readRecord for a record which was only partially written.
+var errTorn = errors.New("durable: torn record")
+
+// appendRecord appends the encoded record to buf.
+func appendRecord(buf []byte, o op, vals []uint64) []byte {
+	body := make([]byte, 1, 1+8*len(vals))
+	body[0] = byte(o)
+	for _, v := range vals {
+		body = binary.LittleEndian.AppendUint64(body, v)
+	}
+
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(body, castagnoli))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(body)))
+	return append(buf, body...)
+}
+
+// readRecord decodes the next record from r, returning its size. It returns io.EOF at the end of the log, and
+// errTorn if the remainder isn't a valid record.
+func readRecord(r io.Reader) (o op, vals []uint64, size int64, err error) {
+	var header [recordHeader]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF {
+			return 0, nil, 0, io.EOF
+		}
+		return 0, nil, 0, errTorn
+	}
+
+	sum, n := binary.LittleEndian.Uint32(header[:]), binary.LittleEndian.Uint32(header[4:])
+	if n == 0 || (n-1)%8 != 0 {
+		return 0, nil, 0, errTorn
+	}
+	// the length may itself be torn, so it's only trusted as far as there's data to back it
+	body, err := io.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil || uint32(len(body)) != n || crc32.Checksum(body, castagnoli) != sum {
+		return 0, nil, 0, errTorn
+	}
+
+	o = op(body[0])
+	for b := body[1:]; len(b) > 0; b = b[8:] {
+		vals = append(vals, binary.LittleEndian.Uint64(b))
+	}
+	switch {
+	case o < opSet || o > opClear,
+		(o == opSetRange || o == opUnsetRange) && len(vals) != 2,
+		o == opClear && len(vals) != 0:
+		return 0, nil, 0, errTorn
+	}
+	return o, vals, recordHeader + int64(n), nil
+}
@@ -1,37 +1,14 @@
 package burger
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/zblach/go-bitset/dense/bools"
 )
 
-type topping uint
-
-const (
-	_ topping = iota
-
-	LETTUCE
-	TOMATO
-	ONION
-	CHEESE
-	MORE_CHEESE
-
-	MUSTARD
-	KETCHUP
-	DONKEY_SAUCE
-	BBQ_SAUCE
-
-	BACON
-	MORE_BACON
-	EVEN_MORE_BACON
-	EXTRA_BACON
-	DOUBLE_EXTRA_BACON
-
-	topping_count uint = iota - 1
-)
-
 type burger struct{ bools.Bitset[topping] }
 
 func New() burger {
@@ -49,6 +26,11 @@ func (b burger) hasBacon() bool {
 	return b.Any(BACON, MORE_BACON, EVEN_MORE_BACON, EXTRA_BACON, DOUBLE_EXTRA_BACON)
 }
 
+// Format prints the toppings, rather than the bitset's internals.
+func (b burger) Format(f fmt.State, verb rune) {
+	b.Bitset.Format(f, verb)
+}
+
 func (b burger) With(t ...topping) burger {
 	new := b.Copy()
 	new.Set(t...)
@@ -68,4 +50,34 @@ func Test_Instantiation(t *testing.T) {
 
 	assert.False(t, want.Get(TOMATO))
 	assert.True(t, want.Get(BACON))
+	assert.Equal(t, "{1, 6, 10}", fmt.Sprint(want))
+}
+
+func Test_Generated(t *testing.T) {
+	order := newToppingSet(LETTUCE, TOMATO, MUSTARD).Union(toppingSetBacon())
+	assert.True(t, order.Overlaps(toppingSetBacon()))
+	assert.False(t, order.Overlaps(toppingSetCheese()))
+	assert.True(t, toppingSetAll().Contains(order))
+	assert.Equal(t, 0, toppingSetNone().Len())
+
+	assert.Equal(t, "{LETTUCE, TOMATO, MUSTARD}", order.Without(toppingSetBacon().Values()...).String())
+
+	parsed, err := parseToppingSet("{LETTUCE, TOMATO, MUSTARD}")
+	assert.NoError(t, err)
+	assert.Equal(t, newToppingSet(LETTUCE, TOMATO, MUSTARD), parsed)
+	_, err = parseToppingSet("PICKLES")
+	assert.Error(t, err)
+
+	data, err := json.Marshal(newToppingSet(ONION, CHEESE))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["ONION","CHEESE"]`, string(data))
+
+	var decoded toppingSet
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.Equal(newToppingSet(CHEESE, ONION)))
+
+	// comparable, so usable as map keys
+	orders := map[toppingSet]int{parsed: 1}
+	orders[newToppingSet(MUSTARD, TOMATO, LETTUCE)]++
+	assert.Equal(t, 2, orders[parsed])
 }
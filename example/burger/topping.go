@@ -0,0 +1,32 @@
+package burger
+
+//go:generate go run github.com/zblach/go-bitset/cmd/bitsetgen -type topping
+
+type topping uint
+
+const (
+	_ topping = iota
+
+	LETTUCE
+	TOMATO
+	ONION
+	CHEESE
+	MORE_CHEESE
+
+	MUSTARD
+	KETCHUP
+	DONKEY_SAUCE
+	BBQ_SAUCE
+
+	BACON
+	MORE_BACON
+	EVEN_MORE_BACON
+	EXTRA_BACON
+	DOUBLE_EXTRA_BACON
+
+	topping_count uint = iota - 1
+)
+
+//bitset:group cheese CHEESE,MORE_CHEESE
+//bitset:group sauce MUSTARD,KETCHUP,DONKEY_SAUCE,BBQ_SAUCE
+//bitset:group bacon BACON,MORE_BACON,EVEN_MORE_BACON,EXTRA_BACON,DOUBLE_EXTRA_BACON
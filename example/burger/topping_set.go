@@ -0,0 +1,241 @@
+// Code generated by "bitsetgen -type topping"; DO NOT EDIT.
+
+package burger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/zblach/go-bitset/dense/fixed"
+)
+
+// toppingSet is a set of topping values. The zero value is an empty set.
+// It's a comparable value; With, Without, Union and Intersect return modified copies.
+type toppingSet struct {
+	b fixed.Set64[topping]
+}
+
+// newToppingSet returns a set of the given values.
+func newToppingSet(vals ...topping) toppingSet {
+	return toppingSet{}.With(vals...)
+}
+
+// toppingSetAll returns the set of every topping constant.
+func toppingSetAll() toppingSet {
+	return newToppingSet(LETTUCE, TOMATO, ONION, CHEESE, MORE_CHEESE, MUSTARD, KETCHUP, DONKEY_SAUCE, BBQ_SAUCE, BACON, MORE_BACON, EVEN_MORE_BACON, EXTRA_BACON, DOUBLE_EXTRA_BACON)
+}
+
+// toppingSetNone returns the empty set.
+func toppingSetNone() toppingSet {
+	return toppingSet{}
+}
+
+// toppingSetCheese returns the cheese group: CHEESE, MORE_CHEESE.
+func toppingSetCheese() toppingSet {
+	return newToppingSet(CHEESE, MORE_CHEESE)
+}
+
+// toppingSetSauce returns the sauce group: MUSTARD, KETCHUP, DONKEY_SAUCE, BBQ_SAUCE.
+func toppingSetSauce() toppingSet {
+	return newToppingSet(MUSTARD, KETCHUP, DONKEY_SAUCE, BBQ_SAUCE)
+}
+
+// toppingSetBacon returns the bacon group: BACON, MORE_BACON, EVEN_MORE_BACON, EXTRA_BACON, DOUBLE_EXTRA_BACON.
+func toppingSetBacon() toppingSet {
+	return newToppingSet(BACON, MORE_BACON, EVEN_MORE_BACON, EXTRA_BACON, DOUBLE_EXTRA_BACON)
+}
+
+// Has reports whether v is in the set.
+func (s toppingSet) Has(v topping) bool {
+	return s.b.Get(v)
+}
+
+// With returns a copy of the set with the values added.
+func (s toppingSet) With(vals ...topping) toppingSet {
+	return toppingSet{s.b.With(vals...)}
+}
+
+// Without returns a copy of the set with the values removed.
+func (s toppingSet) Without(vals ...topping) toppingSet {
+	return toppingSet{s.b.Without(vals...)}
+}
+
+// Union returns the values in either set.
+func (s toppingSet) Union(o toppingSet) toppingSet {
+	return toppingSet{s.b.Union(o.b)}
+}
+
+// Intersect returns the values in both sets.
+func (s toppingSet) Intersect(o toppingSet) toppingSet {
+	return toppingSet{s.b.Intersect(o.b)}
+}
+
+// Overlaps reports whether the sets have any values in common.
+func (s toppingSet) Overlaps(o toppingSet) bool {
+	return s.Intersect(o).Len() > 0
+}
+
+// Contains reports whether every value of o is in the set.
+func (s toppingSet) Contains(o toppingSet) bool {
+	return s.Intersect(o).Len() == o.Len()
+}
+
+// Equal reports whether the sets have the same values.
+func (s toppingSet) Equal(o toppingSet) bool {
+	return s == o
+}
+
+// Len is the number of values in the set.
+func (s toppingSet) Len() int {
+	return int(s.b.Pop())
+}
+
+// Values returns the values in the set, in ascending order.
+func (s toppingSet) Values() []topping {
+	vals := make([]topping, 0, s.Len())
+	it, _ := s.b.Iterate()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+// String returns the names of the values in the set, e.g. "{LETTUCE}".
+func (s toppingSet) String() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range s.Values() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if name, ok := _toppingSetName(v); ok {
+			b.WriteString(name)
+		} else {
+			fmt.Fprintf(&b, "topping(%d)", v)
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// parseToppingSet parses a set of topping names, as returned by String.
+// The braces are optional, and names may be separated by commas, pipes or spaces.
+func parseToppingSet(str string) (toppingSet, error) {
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, "{") && strings.HasSuffix(str, "}") {
+		str = str[1 : len(str)-1]
+	}
+
+	var vals []topping
+	for _, name := range strings.FieldsFunc(str, func(r rune) bool { return r == ',' || r == '|' || unicode.IsSpace(r) }) {
+		v, ok := _toppingSetValue(name)
+		if !ok {
+			return toppingSet{}, fmt.Errorf("toppingSet: unknown topping %q", name)
+		}
+		vals = append(vals, v)
+	}
+	return newToppingSet(vals...), nil
+}
+
+// MarshalJSON encodes the set as a list of names.
+func (s toppingSet) MarshalJSON() ([]byte, error) {
+	vals := s.Values()
+	names := make([]string, len(vals))
+	for i, v := range vals {
+		name, ok := _toppingSetName(v)
+		if !ok {
+			return nil, fmt.Errorf("toppingSet: topping(%d) has no name", v)
+		}
+		names[i] = name
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes a list of names.
+func (s *toppingSet) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+
+	vals := make([]topping, len(names))
+	for i, name := range names {
+		v, ok := _toppingSetValue(name)
+		if !ok {
+			return fmt.Errorf("toppingSet: unknown topping %q", name)
+		}
+		vals[i] = v
+	}
+	*s = newToppingSet(vals...)
+	return nil
+}
+
+func _toppingSetName(v topping) (string, bool) {
+	switch v {
+	case LETTUCE:
+		return "LETTUCE", true
+	case TOMATO:
+		return "TOMATO", true
+	case ONION:
+		return "ONION", true
+	case CHEESE:
+		return "CHEESE", true
+	case MORE_CHEESE:
+		return "MORE_CHEESE", true
+	case MUSTARD:
+		return "MUSTARD", true
+	case KETCHUP:
+		return "KETCHUP", true
+	case DONKEY_SAUCE:
+		return "DONKEY_SAUCE", true
+	case BBQ_SAUCE:
+		return "BBQ_SAUCE", true
+	case BACON:
+		return "BACON", true
+	case MORE_BACON:
+		return "MORE_BACON", true
+	case EVEN_MORE_BACON:
+		return "EVEN_MORE_BACON", true
+	case EXTRA_BACON:
+		return "EXTRA_BACON", true
+	case DOUBLE_EXTRA_BACON:
+		return "DOUBLE_EXTRA_BACON", true
+	}
+	return "", false
+}
+
+func _toppingSetValue(name string) (topping, bool) {
+	switch name {
+	case "LETTUCE":
+		return LETTUCE, true
+	case "TOMATO":
+		return TOMATO, true
+	case "ONION":
+		return ONION, true
+	case "CHEESE":
+		return CHEESE, true
+	case "MORE_CHEESE":
+		return MORE_CHEESE, true
+	case "MUSTARD":
+		return MUSTARD, true
+	case "KETCHUP":
+		return KETCHUP, true
+	case "DONKEY_SAUCE":
+		return DONKEY_SAUCE, true
+	case "BBQ_SAUCE":
+		return BBQ_SAUCE, true
+	case "BACON":
+		return BACON, true
+	case "MORE_BACON":
+		return MORE_BACON, true
+	case "EVEN_MORE_BACON":
+		return EVEN_MORE_BACON, true
+	case "EXTRA_BACON":
+		return EXTRA_BACON, true
+	case "DOUBLE_EXTRA_BACON":
+		return DOUBLE_EXTRA_BACON, true
+	}
+	return 0, false
+}
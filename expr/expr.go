@@ -0,0 +1,195 @@
+package expr
+
+import (
+	"strings"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Op is the kind of operation an expression node performs.
+type Op int
+
+const (
+	OpLeaf Op = iota
+	OpNot
+	OpAnd
+	OpOr
+	OpXor
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpLeaf:
+		return "leaf"
+	case OpNot:
+		return "not"
+	case OpAnd:
+		return "and"
+	case OpOr:
+		return "or"
+	case OpXor:
+		return "xor"
+	}
+	return "unknown"
+}
+
+// Set is a bitset which supports native binary operations, as well as iteration.
+type Set[V bitset.Value, S bitset.Bitset[V]] interface {
+	bitset.Bitset[V]
+	bitset.Binary[V, S]
+	iterable.Iterable[V]
+}
+
+// Node is an element of a lazily-evaluated query expression tree.
+// Nothing is evaluated until the expression is planned and iterated.
+type Node[V bitset.Value] struct {
+	op       Op
+	children []*Node[V]
+
+	// leaves only
+	name   string
+	set    iterable.Iterable[V]
+	native native[V] // nil if the set has no native binary operations
+}
+
+// Leaf wraps any iterable set as an expression. The name is used when printing the expression.
+func Leaf[V bitset.Value](name string, s iterable.Iterable[V]) *Node[V] {
+	return &Node[V]{op: OpLeaf, name: name, set: s}
+}
+
+// Native wraps a set with native binary operations as an expression.
+// When the planner finds compatible sets under the same And or Or node, it uses the native operations instead of merging iterators.
+func Native[V bitset.Value, S Set[V, S]](name string, s S) *Node[V] {
+	return &Node[V]{op: OpLeaf, name: name, set: s, native: nativeSet[V, S]{set: s}}
+}
+
+// And is the intersection of two or more expressions.
+func And[V bitset.Value](a, b *Node[V], more ...*Node[V]) *Node[V] {
+	return group(OpAnd, a, b, more)
+}
+
+// Or is the union of two or more expressions.
+func Or[V bitset.Value](a, b *Node[V], more ...*Node[V]) *Node[V] {
+	return group(OpOr, a, b, more)
+}
+
+// Xor is the symmetric difference of two or more expressions.
+func Xor[V bitset.Value](a, b *Node[V], more ...*Node[V]) *Node[V] {
+	return group(OpXor, a, b, more)
+}
+
+// Not is the complement of an expression.
+// A negated expression is unbounded, and can only be evaluated when it is subtracted from something else.
+func Not[V bitset.Value](a *Node[V]) *Node[V] {
+	return &Node[V]{op: OpNot, children: []*Node[V]{a}}
+}
+
+func group[V bitset.Value](op Op, a, b *Node[V], more []*Node[V]) *Node[V] {
+	children := make([]*Node[V], 0, len(more)+2)
+	children = append(children, a, b)
+	children = append(children, more...)
+
+	return &Node[V]{op: op, children: children}
+}
+
+// Op returns the operation of this node.
+func (n *Node[V]) Op() Op {
+	return n.op
+}
+
+// Name returns the name of a leaf node. It is empty for all other nodes.
+func (n *Node[V]) Name() string {
+	return n.name
+}
+
+// Children returns the operands of this node. Leaves have no children.
+func (n *Node[V]) Children() []*Node[V] {
+	return n.children
+}
+
+// String prints the expression in its canonical form, e.g. `(a | b) & c & !d`.
+// Parentheses are only added where operator precedence requires them.
+func (n *Node[V]) String() string {
+	var sb strings.Builder
+	n.write(&sb)
+	return sb.String()
+}
+
+// precedence ranks operators from loosest to tightest binding: | ^ & !
+func (op Op) precedence() int {
+	switch op {
+	case OpOr:
+		return 1
+	case OpXor:
+		return 2
+	case OpAnd:
+		return 3
+	}
+	return 4
+}
+
+func (n *Node[V]) write(sb *strings.Builder) {
+	switch n.op {
+	case OpLeaf:
+		sb.WriteString(n.name)
+	case OpNot:
+		sb.WriteByte('!')
+		n.children[0].writeOperand(sb, n.op)
+	default:
+		sym := map[Op]string{OpAnd: " & ", OpOr: " | ", OpXor: " ^ "}[n.op]
+		for i, c := range n.children {
+			if i > 0 {
+				sb.WriteString(sym)
+			}
+			c.writeOperand(sb, n.op)
+		}
+	}
+}
+
+// writeOperand writes a child, wrapped in parentheses if it binds looser than its parent.
+// All binary operations are associative, so nested groups of the same operation print flat.
+func (n *Node[V]) writeOperand(sb *strings.Builder, parent Op) {
+	if n.op.precedence() < parent.precedence() {
+		sb.WriteByte('(')
+		n.write(sb)
+		sb.WriteByte(')')
+		return
+	}
+	n.write(sb)
+}
+
+// native provides access to a leaf's native binary operations without knowing its concrete type.
+type native[V bitset.Value] interface {
+	compatible(other native[V]) bool
+	fold(op Op, others []native[V]) native[V]
+	iterable() iterable.Iterable[V]
+}
+
+type nativeSet[V bitset.Value, S Set[V, S]] struct {
+	set S
+}
+
+func (n nativeSet[V, S]) compatible(other native[V]) bool {
+	_, ok := other.(nativeSet[V, S])
+	return ok
+}
+
+// fold combines this set with all others, using only native operations. All others must be compatible.
+func (n nativeSet[V, S]) fold(op Op, others []native[V]) native[V] {
+	acc := n.set
+	for _, o := range others {
+		b := o.(nativeSet[V, S]).set
+		switch op {
+		case OpAnd:
+			acc = acc.And(b)
+		case OpOr:
+			acc = acc.Or(b)
+		}
+	}
+	return nativeSet[V, S]{set: acc}
+}
+
+func (n nativeSet[V, S]) iterable() iterable.Iterable[V] {
+	return n.set
+}
@@ -0,0 +1,90 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/bools"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+func sets() (a, b, c, d *bits.Bitset[uint64, uint]) {
+	a, b, c, d = bits.NewUint64(0), bits.NewUint64(0), bits.NewUint64(0), bits.NewUint64(0)
+	a.Set(1, 2, 3, 4, 5, 6, 7, 8)
+	b.Set(10, 11, 12)
+	c.Set(2, 4, 6, 8, 10, 12)
+	d.Set(4, 10)
+	return
+}
+
+func TestString(t *testing.T) {
+	a, b, c, d := sets()
+	na, nb, nc, nd := Leaf[uint]("a", a), Leaf[uint]("b", b), Leaf[uint]("c", c), Leaf[uint]("d", d)
+
+	assert.Equal(t, "(a | b) & c & !d", And(Or(na, nb), nc, Not(nd)).String())
+	assert.Equal(t, "a & b & c", And(And(na, nb), nc).String())
+	assert.Equal(t, "a ^ b & c | !(a | d)", Or(Xor(na, And(nb, nc)), Not(Or(na, nd))).String())
+}
+
+func TestPlan(t *testing.T) {
+	a, b, c, d := sets()
+	na, nb, nc, nd := Leaf[uint]("a", a), Leaf[uint]("b", b), Leaf[uint]("c", c), Leaf[uint]("d", d)
+
+	p, err := And(Or(na, nb), nc, Not(nd)).Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2, 6, 8, 12}, iterable.Values[uint](p))
+
+	// !(!a | d) == a & !d
+	p, err = Not(Or(Not(na), nd)).Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 3, 5, 6, 7, 8}, iterable.Values[uint](p))
+
+	// a ^ !c ^ !d == a ^ c ^ d
+	p, err = Xor(na, Not(nc), Not(nd)).Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 3, 4, 5, 7, 12}, iterable.Values[uint](p))
+
+	_, err = Or(na, Not(nd)).Plan()
+	assert.ErrorIs(t, err, ErrUnbounded)
+
+	_, err = Not(na).Plan()
+	assert.ErrorIs(t, err, ErrUnbounded)
+}
+
+func TestPlan_Ordering(t *testing.T) {
+	a, b, c, _ := sets()
+
+	p, err := And(Leaf[uint]("a", a), Leaf[uint]("b", b), Leaf[uint]("c", c)).Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, "plan for a & b & c\n"+
+		"  and (est 3)\n"+
+		"    leaf b (est 3)\n"+
+		"    leaf c (est 6)\n"+
+		"    leaf a (est 8)\n", p.Explain())
+}
+
+func TestPlan_Native(t *testing.T) {
+	a, b, c, d := sets()
+	e := bools.New[uint](0)
+	e.Set(6, 12)
+
+	n := And(Or(Native[uint]("a", a), Native[uint]("b", b)), Native[uint]("c", c), Native[uint]("e", e), Not(Leaf[uint]("d", d)))
+	p, err := n.Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, "plan for (a | b) & c & e & !d\n"+
+		"  and-not (est 2)\n"+
+		"    and (est 2)\n"+
+		"      leaf e (est 2)\n"+
+		"      native-and (est 6)\n"+
+		"        leaf c (est 6)\n"+
+		"        native-or (est 11)\n"+
+		"          leaf a (est 8)\n"+
+		"          leaf b (est 3)\n"+
+		"    leaf d (est 2)\n", p.Explain())
+	assert.Equal(t, []uint{6, 12}, iterable.Values[uint](p))
+
+	// plans see changes to the underlying sets
+	d.Set(6)
+	assert.Equal(t, []uint{12}, iterable.Values[uint](p))
+}
@@ -0,0 +1,233 @@
+package expr
+
+import (
+	"fmt"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Resolver looks up the sets referred to by identifiers in a query.
+type Resolver[V bitset.Value] interface {
+	Resolve(name string) (iterable.Iterable[V], bool)
+}
+
+// Sets is a Resolver for a fixed collection of named sets.
+type Sets[V bitset.Value] map[string]iterable.Iterable[V]
+
+// Resolve implements Resolver
+func (s Sets[V]) Resolve(name string) (iterable.Iterable[V], bool) {
+	set, ok := s[name]
+	return set, ok
+}
+
+// SyntaxError describes a malformed query, or an identifier which could not be resolved.
+type SyntaxError struct {
+	Pos int // byte offset into the query
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("expr: offset %d: %s", e.Pos, e.Msg)
+}
+
+// Parse reads a boolean query over named sets, e.g. `(premium | trial) & !banned & region_eu`.
+//
+// The grammar, from loosest to tightest binding:
+//
+//	or    = xor { "|" xor }
+//	xor   = and { "^" and }
+//	and   = unary { "&" unary }
+//	unary = ( "!" | "-" ) unary | "(" or ")" | identifier
+//
+// Identifiers are made of letters, digits, '_' and '.', and are resolved as they're parsed.
+// The returned expression prints back in canonical form through its String method.
+func Parse[V bitset.Value](query string, r Resolver[V]) (*Node[V], error) {
+	p := &parser[V]{src: query, r: r}
+	p.next()
+
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok != tokEOF {
+		return nil, p.errorf("unexpected %s", p.describe())
+	}
+	return n, nil
+}
+
+// Eval parses and plans a query, returning its lazily-evaluated result.
+func Eval[V bitset.Value](query string, r Resolver[V]) (iterable.Iterable[V], error) {
+	n, err := Parse(query, r)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := n.Plan()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type token int
+
+const (
+	tokEOF token = iota
+	tokIdent
+	tokAnd
+	tokOr
+	tokXor
+	tokNot
+	tokLParen
+	tokRParen
+	tokInvalid
+)
+
+type parser[V bitset.Value] struct {
+	src string
+	r   Resolver[V]
+
+	// current token
+	tok token
+	pos int
+	lit string
+
+	off int // read offset
+}
+
+// next advances to the next token.
+func (p *parser[V]) next() {
+	for p.off < len(p.src) && isSpace(p.src[p.off]) {
+		p.off++
+	}
+
+	p.pos = p.off
+	if p.off >= len(p.src) {
+		p.tok, p.lit = tokEOF, ""
+		return
+	}
+
+	c := p.src[p.off]
+	p.off++
+	p.lit = string(c)
+
+	switch c {
+	case '&':
+		p.tok = tokAnd
+	case '|':
+		p.tok = tokOr
+	case '^':
+		p.tok = tokXor
+	case '!', '-':
+		p.tok = tokNot
+	case '(':
+		p.tok = tokLParen
+	case ')':
+		p.tok = tokRParen
+	default:
+		if !isIdent(c) {
+			p.tok = tokInvalid
+			return
+		}
+		for p.off < len(p.src) && isIdent(p.src[p.off]) {
+			p.off++
+		}
+		p.tok, p.lit = tokIdent, p.src[p.pos:p.off]
+	}
+}
+
+func (p *parser[V]) or() (*Node[V], error) {
+	return p.binary(OpOr, tokOr, p.xor)
+}
+
+func (p *parser[V]) xor() (*Node[V], error) {
+	return p.binary(OpXor, tokXor, p.and)
+}
+
+func (p *parser[V]) and() (*Node[V], error) {
+	return p.binary(OpAnd, tokAnd, p.unary)
+}
+
+// binary parses a run of operands joined by the same operator into a single node.
+func (p *parser[V]) binary(op Op, tok token, operand func() (*Node[V], error)) (*Node[V], error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*Node[V]{first}
+	for p.tok == tok {
+		p.next()
+		n, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return group(op, children[0], children[1], children[2:]), nil
+}
+
+func (p *parser[V]) unary() (*Node[V], error) {
+	switch p.tok {
+	case tokNot:
+		p.next()
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(n), nil
+
+	case tokLParen:
+		open := p.pos
+		p.next()
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != tokRParen {
+			return nil, p.errorf("expected ')' to close '(' at offset %d, found %s", open, p.describe())
+		}
+		p.next()
+		return n, nil
+
+	case tokIdent:
+		set, ok := p.r.Resolve(p.lit)
+		if !ok {
+			return nil, p.errorf("unknown set %q", p.lit)
+		}
+		n := Leaf(p.lit, set)
+		p.next()
+		return n, nil
+	}
+
+	return nil, p.errorf("expected a set name, '!' or '(', found %s", p.describe())
+}
+
+// describe names the current token for error messages.
+func (p *parser[V]) describe() string {
+	switch p.tok {
+	case tokEOF:
+		return "end of query"
+	case tokIdent:
+		return fmt.Sprintf("identifier %q", p.lit)
+	}
+	return fmt.Sprintf("%q", p.lit)
+}
+
+func (p *parser[V]) errorf(format string, args ...any) error {
+	return &SyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '.' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
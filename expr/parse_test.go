@@ -0,0 +1,75 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/iterable"
+	mapset "github.com/zblach/go-bitset/sparse/map"
+)
+
+func accounts() Sets[uint] {
+	premium, trial, banned, eu := bits.NewUint(0), bits.NewUint(0), mapset.New[uint](), bits.NewUint(0)
+	premium.Set(1, 2, 3, 4)
+	trial.Set(7, 8, 9)
+	banned.Set(3, 8)
+	eu.Set(2, 3, 4, 8, 9, 10)
+
+	return Sets[uint]{"premium": premium, "trial": trial, "banned": banned, "region_eu": eu}
+}
+
+func TestParse(t *testing.T) {
+	n, err := Parse[uint]("(premium | trial) & !banned & region_eu", accounts())
+	assert.NoError(t, err)
+	assert.Equal(t, "(premium | trial) & !banned & region_eu", n.String())
+
+	p, err := n.Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2, 4, 9}, iterable.Values[uint](p))
+
+	it, err := Eval[uint]("premium ^ region_eu & -banned", accounts())
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 3, 9, 10}, iterable.Values(it))
+}
+
+func TestParse_Canonical(t *testing.T) {
+	for query, canonical := range map[string]string{
+		"premium":                          "premium",
+		"((premium))":                      "premium",
+		"premium|trial&banned":             "premium | trial & banned",
+		"(premium|trial)&banned":           "(premium | trial) & banned",
+		"premium & (trial & banned)":       "premium & trial & banned",
+		"-(premium ^ trial) | !!region_eu": "!(premium ^ trial) | !!region_eu",
+		"premium ^ (trial | banned)":       "premium ^ (trial | banned)",
+	} {
+		n, err := Parse[uint](query, accounts())
+		if assert.NoError(t, err, query) {
+			assert.Equal(t, canonical, n.String(), query)
+
+			again, err := Parse[uint](n.String(), accounts())
+			assert.NoError(t, err, query)
+			assert.Equal(t, canonical, again.String(), query)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for query, want := range map[string]SyntaxError{
+		"":                     {Pos: 0, Msg: "expected a set name, '!' or '(', found end of query"},
+		"premium &":            {Pos: 9, Msg: "expected a set name, '!' or '(', found end of query"},
+		"premium & gold":       {Pos: 10, Msg: `unknown set "gold"`},
+		"(premium | trial":     {Pos: 16, Msg: "expected ')' to close '(' at offset 0, found end of query"},
+		"premium trial":        {Pos: 8, Msg: `unexpected identifier "trial"`},
+		"premium & trial)":     {Pos: 15, Msg: `unexpected ")"`},
+		"premium & $":          {Pos: 10, Msg: `expected a set name, '!' or '(', found "$"`},
+		"premium | (& banned)": {Pos: 11, Msg: `expected a set name, '!' or '(', found "&"`},
+	} {
+		_, err := Parse[uint](query, accounts())
+
+		var se *SyntaxError
+		if assert.ErrorAs(t, err, &se, query) {
+			assert.Equal(t, want, *se, query)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// ErrUnbounded is returned when an expression evaluates to a complement, e.g. `!a` or `a | !b`.
+// Negations can only be evaluated when something bounded is subtracted from, as in `a & !b`.
+var ErrUnbounded = errors.New("expr: expression is unbounded (negated at the top level)")
+
+type stepKind int
+
+const (
+	stepLeaf      stepKind = iota
+	stepAnd                // streaming intersection
+	stepOr                 // streaming union
+	stepXor                // streaming symmetric difference
+	stepAndNot             // streaming difference: children[0] minus all others
+	stepNativeAnd          // backend intersection
+	stepNativeOr           // backend union
+)
+
+func (k stepKind) String() string {
+	return [...]string{"leaf", "and", "or", "xor", "and-not", "native-and", "native-or"}[k]
+}
+
+// step is a single node of an evaluation plan.
+type step[V bitset.Value] struct {
+	kind     stepKind
+	leaf     *Node[V]
+	children []*step[V]
+	est      uint
+}
+
+// Plan is an evaluation strategy for an expression. It is iterable, and re-evaluates against the current contents of its sets each time it's iterated.
+type Plan[V bitset.Value] struct {
+	expr *Node[V]
+	root *step[V]
+}
+
+// Plan builds an evaluation plan for the expression.
+// Negations are pushed down until they can be evaluated as differences, operands are ordered by their estimated sizes,
+// and compatible native sets are combined using their own binary operations.
+func (n *Node[V]) Plan() (*Plan[V], error) {
+	root, negated := plan(n)
+	if negated {
+		return nil, fmt.Errorf("%w: %s", ErrUnbounded, n)
+	}
+	return &Plan[V]{expr: n, root: root}, nil
+}
+
+// Iterate implements iterable.Iterable
+func (p *Plan[V]) Iterate() (iterable.Iter[V], uint) {
+	return p.root.iterable().Iterate()
+}
+
+// Explain describes the chosen plan, one step per line, with the estimated size of each step's result.
+func (p *Plan[V]) Explain() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "plan for %s\n", p.expr)
+	p.root.explain(&sb, 1)
+	return sb.String()
+}
+
+func (s *step[V]) explain(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	if s.kind == stepLeaf {
+		fmt.Fprintf(sb, "leaf %s (est %d)\n", s.leaf.name, s.est)
+		return
+	}
+	fmt.Fprintf(sb, "%s (est %d)\n", s.kind, s.est)
+	for _, c := range s.children {
+		c.explain(sb, depth+1)
+	}
+}
+
+// iterable assembles the lazy iterable for this step.
+func (s *step[V]) iterable() iterable.Iterable[V] {
+	switch s.kind {
+	case stepLeaf:
+		return s.leaf.set
+	case stepNativeAnd, stepNativeOr:
+		return s.eval().iterable()
+	}
+
+	its := make([]iterable.Iterable[V], len(s.children))
+	for i, c := range s.children {
+		its[i] = c.iterable()
+	}
+
+	switch s.kind {
+	case stepAnd:
+		return iterable.And(its[0], its[1], its[2:]...)
+	case stepOr:
+		return iterable.Or(its[0], its[1], its[2:]...)
+	case stepXor:
+		return iterable.Xor(its[0], its[1], its[2:]...)
+	default: // stepAndNot
+		return iterable.AndNot(its[0], its[1:]...)
+	}
+}
+
+// proto returns a native set representative of this step's result, or nil if it has no native result.
+func (s *step[V]) proto() native[V] {
+	switch s.kind {
+	case stepLeaf:
+		return s.leaf.native
+	case stepNativeAnd, stepNativeOr:
+		return s.children[0].proto()
+	}
+	return nil
+}
+
+// eval computes the result of a native step, or returns the native set of a leaf.
+func (s *step[V]) eval() native[V] {
+	if s.kind == stepLeaf {
+		return s.leaf.native
+	}
+
+	op := OpAnd
+	if s.kind == stepNativeOr {
+		op = OpOr
+	}
+	others := make([]native[V], 0, len(s.children)-1)
+	for _, c := range s.children[1:] {
+		others = append(others, c.eval())
+	}
+	return s.children[0].eval().fold(op, others)
+}
+
+// plan converts an expression into a plan step. If negated is true, the step's result is the complement of the expression.
+func plan[V bitset.Value](n *Node[V]) (s *step[V], negated bool) {
+	switch n.op {
+	case OpLeaf:
+		return &step[V]{kind: stepLeaf, leaf: n, est: estimate(n.set)}, false
+
+	case OpNot:
+		s, negated = plan(n.children[0])
+		return s, !negated
+
+	case OpXor:
+		// a ^ !b == !(a ^ b)
+		children := make([]*step[V], len(n.children))
+		for i, c := range n.children {
+			var neg bool
+			children[i], neg = plan(c)
+			negated = negated != neg
+		}
+		return combine(stepXor, children), negated
+	}
+
+	var pos, neg []*step[V]
+	for _, c := range n.children {
+		if s, negated := plan(c); negated {
+			neg = append(neg, s)
+		} else {
+			pos = append(pos, s)
+		}
+	}
+
+	if n.op == OpAnd {
+		switch {
+		case len(pos) == 0:
+			// !a & !b == !(a | b)
+			return combine(stepOr, neg), true
+		case len(neg) == 0:
+			return combine(stepAnd, pos), false
+		default:
+			// a & !b == a - b
+			return andNot(combine(stepAnd, pos), neg), false
+		}
+	}
+
+	// OpOr
+	switch {
+	case len(neg) == 0:
+		return combine(stepOr, pos), false
+	case len(pos) == 0:
+		// !a | !b == !(a & b)
+		return combine(stepAnd, neg), true
+	default:
+		// a | !b == !(b - a)
+		return andNot(combine(stepAnd, neg), pos), true
+	}
+}
+
+// combine joins steps with a streaming operation, flattening nested steps of the same kind and ordering them by size.
+// Groups of compatible native steps within an And or Or are split out into native steps.
+func combine[V bitset.Value](kind stepKind, steps []*step[V]) *step[V] {
+	children := make([]*step[V], 0, len(steps))
+	for _, s := range steps {
+		if s.kind == kind {
+			children = append(children, s.children...)
+		} else {
+			children = append(children, s)
+		}
+	}
+
+	if kind == stepAnd || kind == stepOr {
+		children = nativeGroups(kind, children)
+	}
+	if len(children) == 1 {
+		return children[0]
+	}
+
+	sortSteps(kind, children)
+	return &step[V]{kind: kind, children: children, est: estimateOf(kind, children)}
+}
+
+// nativeGroups replaces sets of mutually compatible native steps with a single native step.
+func nativeGroups[V bitset.Value](kind stepKind, children []*step[V]) []*step[V] {
+	nativeKind := stepNativeAnd
+	if kind == stepOr {
+		nativeKind = stepNativeOr
+	}
+
+	var groups [][]*step[V]
+	rest := make([]*step[V], 0, len(children))
+next:
+	for _, c := range children {
+		proto := c.proto()
+		if proto == nil {
+			rest = append(rest, c)
+			continue
+		}
+		for i, g := range groups {
+			if g[0].proto().compatible(proto) {
+				groups[i] = append(g, c)
+				continue next
+			}
+		}
+		groups = append(groups, []*step[V]{c})
+	}
+
+	for _, g := range groups {
+		if len(g) == 1 {
+			rest = append(rest, g[0])
+			continue
+		}
+		// native operations copy their result, so the cheapest operand goes first
+		sortSteps(kind, g)
+		rest = append(rest, &step[V]{kind: nativeKind, children: g, est: estimateOf(kind, g)})
+	}
+	return rest
+}
+
+func andNot[V bitset.Value](base *step[V], exclude []*step[V]) *step[V] {
+	sortSteps(stepOr, exclude)
+
+	children := make([]*step[V], 0, len(exclude)+1)
+	children = append(children, base)
+	children = append(children, exclude...)
+
+	return &step[V]{kind: stepAndNot, children: children, est: base.est}
+}
+
+// sortSteps orders intersections smallest-first, and everything else largest-first.
+func sortSteps[V bitset.Value](kind stepKind, steps []*step[V]) {
+	sort.SliceStable(steps, func(i, j int) bool {
+		if kind == stepAnd {
+			return steps[i].est < steps[j].est
+		}
+		return steps[i].est > steps[j].est
+	})
+}
+
+func estimateOf[V bitset.Value](kind stepKind, children []*step[V]) (est uint) {
+	if kind == stepAnd {
+		est = children[0].est
+		for _, c := range children[1:] {
+			if c.est < est {
+				est = c.est
+			}
+		}
+		return
+	}
+	for _, c := range children {
+		est += c.est
+	}
+	return
+}
+
+// estimate uses the population of a set if it's available cheaply, and the size reported by Iterate otherwise.
+func estimate[V bitset.Value](s iterable.Iterable[V]) uint {
+	if p, ok := s.(interface{ Pop() uint }); ok {
+		return p.Pop()
+	}
+	_, size := s.Iterate()
+	return size
+}
+
+var _ iterable.Iterable[uint] = (*Plan[uint])(nil)
@@ -0,0 +1,243 @@
+package bitset
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Members is an ascending stream of a set's members. Every backend's iterator satisfies it.
+type Members[V Value] interface {
+	Next() (V, bool)
+}
+
+const (
+	// maxEntries is the number of members, or ranges, printed when no precision is given.
+	maxEntries = 64
+	// maxDigits is the number of binary or hex digits printed when no precision is given.
+	maxDigits = 256
+)
+
+// String renders members as "{1, 3, 5-9}", collapsing runs of three or more into ranges.
+// Like %v, it prints at most 64 entries.
+func String[V Value](members Members[V]) string {
+	return StringRuns(singles(members))
+}
+
+// StringRuns is String, from the ascending, inclusive runs of members of a set, as returned by next until it reports
+// false. Sets which store runs print in time proportional to them, rather than to their members.
+func StringRuns[V Value](next func() (start, end V, ok bool)) string {
+	var b strings.Builder
+	entries(&b, next, true, maxEntries, nil)
+	return b.String()
+}
+
+// Format implements fmt.Formatter on behalf of a set, so that every backend prints the same way.
+// info may be nil, in which case %+v omits the statistics.
+//
+//	%v, %s  members, with runs of three or more collapsed into ranges: {1, 3, 5-9}
+//	%+v     the same, followed by statistics: {1, 3, 5-9} (pop 7, len 64, cap 64)
+//	%d      every member: {1, 3, 5, 6, 7, 8, 9}
+//	%b      the set as a binary number, where bit i is set for member i: 1111101010
+//	%x      the same, in hex: 3ea. The space flag separates 64-bit words, and # adds a 0b or 0x prefix.
+//
+// The precision caps the number of entries (%v, %d) or digits (%b, %x) which are printed, defaulting to 64 entries
+// or 256 digits. Truncated output ends with "… +N more" members, or starts with "…" in place of the high digits.
+// The width pads the output with spaces, on the left unless the - flag is given. Once the entries are printed, the
+// members left over are counted from info's Pop, rather than visited.
+func Format[V Value](f fmt.State, verb rune, members Members[V], info Inspect[V]) {
+	FormatRuns(f, verb, singles(members), info)
+}
+
+// FormatRuns is Format, from the ascending, inclusive runs of members of a set, as returned by next until it reports
+// false.
+func FormatRuns[V Value](f fmt.State, verb rune, next func() (start, end V, ok bool), info Inspect[V]) {
+	var b strings.Builder
+
+	switch verb {
+	case 'v', 's', 'd':
+		limit, ok := f.Precision()
+		if !ok {
+			limit = maxEntries
+		}
+		entries(&b, next, verb != 'd', limit, info)
+
+		if verb == 'v' && f.Flag('+') && info != nil {
+			fmt.Fprintf(&b, " (pop %d, len %d, cap %d)", info.Pop(), info.Len(), info.Cap())
+		}
+	case 'b', 'x', 'X':
+		limit, ok := f.Precision()
+		if !ok {
+			limit = maxDigits
+		}
+		digits(&b, next, verb, limit, f.Flag('#'), f.Flag(' '))
+	default:
+		fmt.Fprintf(&b, "%%!%c(bitset)", verb)
+	}
+
+	out := b.String()
+	if width, ok := f.Width(); ok {
+		if pad := width - len([]rune(out)); pad > 0 {
+			if f.Flag('-') {
+				out += strings.Repeat(" ", pad)
+			} else {
+				out = strings.Repeat(" ", pad) + out
+			}
+		}
+	}
+	fmt.Fprint(f, out)
+}
+
+// singles returns each member as a run of its own. entries joins them up.
+func singles[V Value](members Members[V]) func() (V, V, bool) {
+	return func() (V, V, bool) {
+		v, ok := members.Next()
+		return v, v, ok
+	}
+}
+
+// entries writes up to limit members, or ranges of members, in braces. It stops there, and counts the rest from
+// info's Pop, or from the runs left when info is nil.
+func entries[V Value](b *strings.Builder, next func() (V, V, bool), ranges bool, limit int, info Inspect[V]) {
+	b.WriteByte('{')
+
+	written := 0
+	seen, shown := uint64(0), uint64(0) // members taken from next, and printed
+	emit := func(start, end V) bool {
+		if written == limit {
+			return false
+		}
+		if written > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(strconv.FormatUint(uint64(start), 10))
+		if start != end {
+			b.WriteByte('-')
+			b.WriteString(strconv.FormatUint(uint64(end), 10))
+		}
+		written++
+		shown = add(shown, span(start, end))
+		return true
+	}
+	take := func() (V, V, bool) {
+		start, end, ok := next()
+		if ok {
+			seen = add(seen, span(start, end))
+		}
+		return start, end, ok
+	}
+
+	full := false
+	start, end, ok := take()
+	for ok && !full {
+		s, e, more := take()
+		if more && ranges && s == end+1 {
+			end = e
+			continue
+		}
+
+		// only runs of three or more are worth collapsing
+		if ranges && end-start >= 2 {
+			full = !emit(start, end)
+		} else {
+			for u := start; !full; u++ {
+				full = !emit(u, u)
+				if u == end {
+					break
+				}
+			}
+		}
+		start, end, ok = s, e, more
+	}
+
+	if full {
+		var rest uint64
+		if info != nil {
+			if pop := uint64(info.Pop()); pop > shown {
+				rest = pop - shown
+			}
+		} else {
+			for _, _, ok := take(); ok; _, _, ok = take() {
+			}
+			rest = seen - shown
+		}
+		if written > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(b, "… +%d more", rest)
+	}
+	b.WriteByte('}')
+}
+
+// digits writes the members as a binary or hex number, keeping at most limit of the lowest digits. Runs beyond them
+// aren't read.
+func digits[V Value](b *strings.Builder, next func() (V, V, bool), verb rune, limit int, prefix, words bool) {
+	bits, alphabet := uint64(4), "0123456789abcdef"
+	switch verb {
+	case 'b':
+		bits = 1
+	case 'X':
+		alphabet = "0123456789ABCDEF"
+	}
+
+	var low []byte // the values of the lowest digits
+	count := uint64(0)
+	for start, end, ok := next(); ok && count <= uint64(limit); start, end, ok = next() {
+		count = add(uint64(end)/bits, 1)
+		for v := uint64(start); v/bits < uint64(limit); v++ {
+			d := v / bits
+			for uint64(len(low)) <= d {
+				low = append(low, 0)
+			}
+			low[d] |= 1 << (v % bits)
+			if v == uint64(end) {
+				break
+			}
+		}
+	}
+
+	if prefix {
+		if verb == 'b' {
+			b.WriteString("0b")
+		} else {
+			b.WriteString("0x")
+		}
+	}
+	if count == 0 {
+		b.WriteByte('0')
+		return
+	}
+
+	shown := count
+	if shown > uint64(limit) {
+		shown = uint64(limit)
+		b.WriteString("…")
+	}
+
+	perWord := 64 / bits
+	for i := shown; i > 0; i-- {
+		d := i - 1
+		if words && verb != 'b' && i != shown && i%perWord == 0 {
+			b.WriteByte(' ')
+		}
+		if d < uint64(len(low)) {
+			b.WriteByte(alphabet[low[d]])
+		} else {
+			b.WriteByte('0')
+		}
+	}
+}
+
+// span is the number of values from start to end, inclusive, saturating when that's every uint64.
+func span[V Value](start, end V) uint64 {
+	return add(uint64(end-start), 1)
+}
+
+// add returns a+b, saturating rather than wrapping.
+func add(a, b uint64) uint64 {
+	if a+b < a {
+		return math.MaxUint64
+	}
+	return a + b
+}
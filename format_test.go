@@ -0,0 +1,103 @@
+package bitset
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// members is a minimal set, for exercising the formatter.
+type members []uint
+
+func (m *members) Next() (uint, bool) {
+	if len(*m) == 0 {
+		return 0, false
+	}
+	v := (*m)[0]
+	*m = (*m)[1:]
+	return v, true
+}
+
+type set struct{ vals []uint }
+
+func (s set) Format(f fmt.State, verb rune) {
+	m := members(append([]uint(nil), s.vals...))
+	Format[uint](f, verb, &m, s)
+}
+
+func (s set) Pop() uint  { return uint(len(s.vals)) }
+func (s set) Len() int   { return 64 }
+func (s set) Cap() int   { return 128 }
+func (s set) Bytes() int { return 16 }
+
+func (s set) Stats() Stats[uint] {
+	m := members(append([]uint(nil), s.vals...))
+	return Measure[uint](s.Bytes(), &m)
+}
+
+func TestString(t *testing.T) {
+	for want, vals := range map[string][]uint{
+		"{}":              nil,
+		"{0}":             {0},
+		"{1, 2}":          {1, 2},
+		"{1, 3, 5-9}":     {1, 3, 5, 6, 7, 8, 9},
+		"{0-2, 4-6, 100}": {0, 1, 2, 4, 5, 6, 100},
+	} {
+		m := members(vals)
+		assert.Equal(t, want, String[uint](&m))
+	}
+}
+
+func TestFormat(t *testing.T) {
+	s := set{[]uint{1, 3, 5, 6, 7, 8, 9}}
+
+	for format, want := range map[string]string{
+		"%v":    "{1, 3, 5-9}",
+		"%s":    "{1, 3, 5-9}",
+		"%+v":   "{1, 3, 5-9} (pop 7, len 64, cap 128)",
+		"%d":    "{1, 3, 5, 6, 7, 8, 9}",
+		"%.2v":  "{1, 3, … +5 more}",
+		"%.3d":  "{1, 3, 5, … +4 more}",
+		"%.0v":  "{… +7 more}",
+		"%b":    "1111101010",
+		"%#b":   "0b1111101010",
+		"%.4b":  "…1010",
+		"%x":    "3ea",
+		"%X":    "3EA",
+		"%#x":   "0x3ea",
+		"%14v":  "   {1, 3, 5-9}",
+		"%-14v": "{1, 3, 5-9}   ",
+		"%q":    "%!q(bitset)",
+	} {
+		assert.Equal(t, want, fmt.Sprintf(format, s), format)
+	}
+
+	assert.Equal(t, "0", fmt.Sprintf("%x", set{}))
+	assert.Equal(t, "1 0000000000000001", fmt.Sprintf("% x", set{[]uint{0, 64}}))
+	assert.Equal(t, "10000000000000001", fmt.Sprintf("%x", set{[]uint{0, 64}}))
+}
+
+func TestStringRuns(t *testing.T) {
+	runs := func(rs ...[2]uint64) func() (uint64, uint64, bool) {
+		return func() (uint64, uint64, bool) {
+			if len(rs) == 0 {
+				return 0, 0, false
+			}
+			r := rs[0]
+			rs = rs[1:]
+			return r[0], r[1], true
+		}
+	}
+
+	assert.Equal(t, "{1, 2, 4-8, 10-18446744073709551615}", StringRuns(runs(
+		[2]uint64{1, 1}, [2]uint64{2, 2}, [2]uint64{4, 6}, [2]uint64{7, 8}, [2]uint64{10, math.MaxUint64},
+	)), "adjacent runs are joined")
+
+	// the rest are counted from the runs, saturating
+	var b strings.Builder
+	entries(&b, runs([2]uint64{0, math.MaxUint64}), true, 0, nil)
+	assert.Equal(t, "{… +18446744073709551615 more}", b.String())
+}
@@ -0,0 +1,41 @@
+// Package backends lists every mutable backend, for the tests which run the same checks across all of them.
+package backends
+
+import (
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/bools"
+	"github.com/zblach/go-bitset/dense/fixed"
+	"github.com/zblach/go-bitset/dense/hier"
+	"github.com/zblach/go-bitset/dense/window"
+	"github.com/zblach/go-bitset/iterable"
+	mapset "github.com/zblach/go-bitset/sparse/map"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+// Set is what every backend implements. Tests assert whatever else they need.
+type Set[V bitset.Value] interface {
+	bitset.Bitset[V]
+	iterable.Iterable[V]
+}
+
+// All returns a constructor of an empty set, for every backend which can hold any V, by name.
+// A new backend only needs adding here to be covered by every test which uses it.
+func All[V bitset.Value]() map[string]func() Set[V] {
+	return map[string]func() Set[V]{
+		"bits8":  func() Set[V] { return bits.New[uint8, V](0) },
+		"bits64": func() Set[V] { return bits.New[uint64, V](0) },
+		"bools":  func() Set[V] { return bools.New[V](0) },
+		"hier":   func() Set[V] { return hier.New[V](0) },
+		"window": func() Set[V] { return window.New[V](0, 0) },
+		"map":    func() Set[V] { return mapset.New[V]() },
+		"range":  func() Set[V] { return rangeset.New[V]() },
+	}
+}
+
+// WithFixed is All, along with the smallest fixed-size set, which can only hold values below 256.
+func WithFixed[V bitset.Value]() map[string]func() Set[V] {
+	all := All[V]()
+	all["fixed"] = func() Set[V] { return &fixed.Set256[V]{} }
+	return all
+}
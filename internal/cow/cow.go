@@ -0,0 +1,211 @@
+// Package cow lets the mutable backends take O(1) snapshots, by saving the original contents of their storage only
+// as it's modified.
+//
+// Each snapshot holds a generation. When the owner modifies a page, or a key, for the first time since the latest
+// snapshot, it first saves the original into that generation. Generations are linked from older to newer, so a
+// snapshot finds the contents as of when it was taken in the first generation, starting from its own, which saved
+// them, and otherwise in the live storage. Each snapshot is released when it's no longer needed, and saving stops once
+// every one has been; older generations are garbage collected with the snapshots which refer to them.
+//
+// Nothing here is locked. Logs are guarded by their owner's lock, and generations are read under its read lock.
+package cow
+
+// PageSize is the number of elements saved at a time.
+const PageSize = 512
+
+// chain counts the unreleased snapshots of a run of linked generations.
+type chain struct {
+	live int
+}
+
+// Log records the pages of a slice which are modified after a snapshot.
+type Log[T any] struct {
+	cur *Gen[T]
+}
+
+// Gen holds the pages saved for the snapshots taken at one point.
+type Gen[T any] struct {
+	pages  map[int][]T
+	frozen []T // the whole slice, once the owner replaced it
+	done   bool
+	next   *Gen[T]
+	chain  *chain
+}
+
+// Snapshot returns the generation for a new snapshot. Consecutive snapshots, with no modifications in between,
+// share one. Each must be released.
+func (l *Log[T]) Snapshot() *Gen[T] {
+	if l.cur != nil && len(l.cur.pages) == 0 {
+		l.cur.chain.live++
+		return l.cur
+	}
+	g := &Gen[T]{pages: map[int][]T{}, chain: &chain{}}
+	if l.cur != nil {
+		l.cur.next, g.chain = g, l.cur.chain
+	}
+	g.chain.live++
+	l.cur = g
+	return g
+}
+
+// Release is called once for each snapshot's generation, when the snapshot won't be read again. Saving stops once
+// every snapshot taken since the last Freeze has been released.
+func (l *Log[T]) Release(g *Gen[T]) {
+	if g.chain.live--; g.chain.live == 0 && l.cur != nil && l.cur.chain == g.chain {
+		l.cur = nil
+	}
+}
+
+// Touch saves the pages of s holding elements lo through hi, before they're modified.
+func (l *Log[T]) Touch(s []T, lo, hi int) {
+	if l.cur == nil {
+		return
+	}
+	if hi >= len(s) {
+		hi = len(s) - 1
+	}
+	if lo > hi {
+		return
+	}
+	for p := lo / PageSize; p <= hi/PageSize; p++ {
+		if _, ok := l.cur.pages[p]; !ok {
+			l.cur.pages[p] = append([]T(nil), page(s, p)...)
+		}
+	}
+}
+
+// Active reports whether modifications are being saved for any snapshots.
+func (l *Log[T]) Active() bool {
+	return l.cur != nil
+}
+
+// Freeze hands s to the snapshots, as the owner is replacing it and won't modify it again.
+func (l *Log[T]) Freeze(s []T) {
+	if l.cur != nil {
+		l.cur.frozen, l.cur.done = s, true
+		l.cur = nil
+	}
+}
+
+// Page returns page p as of g, given the live slice s. The result must not be modified.
+func (g *Gen[T]) Page(s []T, p int) []T {
+	for ; g != nil; g = g.next {
+		if saved, ok := g.pages[p]; ok {
+			return saved
+		}
+		if g.done {
+			return page(g.frozen, p)
+		}
+	}
+	return page(s, p)
+}
+
+// page returns the elements of page p of s, which may be fewer than PageSize.
+func page[T any](s []T, p int) []T {
+	lo, hi := p*PageSize, (p+1)*PageSize
+	if lo > len(s) {
+		lo = len(s)
+	}
+	if hi > len(s) {
+		hi = len(s)
+	}
+	return s[lo:hi]
+}
+
+// KeyLog records the membership of keys which are modified after a snapshot, for sets backed by a map[K]E.
+type KeyLog[K comparable, E any] struct {
+	cur *KeyGen[K, E]
+}
+
+// KeyGen holds the keys saved for the snapshots taken at one point.
+type KeyGen[K comparable, E any] struct {
+	keys   map[K]bool
+	frozen map[K]E
+	done   bool
+	next   *KeyGen[K, E]
+	chain  *chain
+}
+
+// Snapshot returns the generation for a new snapshot. Each must be released.
+func (l *KeyLog[K, E]) Snapshot() *KeyGen[K, E] {
+	if l.cur != nil && len(l.cur.keys) == 0 {
+		l.cur.chain.live++
+		return l.cur
+	}
+	g := &KeyGen[K, E]{keys: map[K]bool{}, chain: &chain{}}
+	if l.cur != nil {
+		l.cur.next, g.chain = g, l.cur.chain
+	}
+	g.chain.live++
+	l.cur = g
+	return g
+}
+
+// Release is Log.Release, for keys.
+func (l *KeyLog[K, E]) Release(g *KeyGen[K, E]) {
+	if g.chain.live--; g.chain.live == 0 && l.cur != nil && l.cur.chain == g.chain {
+		l.cur = nil
+	}
+}
+
+// Active reports whether modifications are being saved for any snapshots.
+func (l *KeyLog[K, E]) Active() bool {
+	return l.cur != nil
+}
+
+// Touch saves whether k is a member, before it's added or removed.
+func (l *KeyLog[K, E]) Touch(k K, member bool) {
+	if l.cur == nil {
+		return
+	}
+	if _, ok := l.cur.keys[k]; !ok {
+		l.cur.keys[k] = member
+	}
+}
+
+// Freeze hands m to the snapshots, as the owner is replacing it and won't modify it again.
+func (l *KeyLog[K, E]) Freeze(m map[K]E) {
+	if l.cur != nil {
+		l.cur.frozen, l.cur.done = m, true
+		l.cur = nil
+	}
+}
+
+// Member reports whether k was a member as of g, given the live map m.
+func (g *KeyGen[K, E]) Member(m map[K]E, k K) bool {
+	for ; g != nil; g = g.next {
+		if member, ok := g.keys[k]; ok {
+			return member
+		}
+		if g.done {
+			_, ok := g.frozen[k]
+			return ok
+		}
+	}
+	_, ok := m[k]
+	return ok
+}
+
+// Keys calls fn with every key which was a member as of g, given the live map m, in no particular order.
+func (g *KeyGen[K, E]) Keys(m map[K]E, fn func(K)) {
+	seen := map[K]bool{}
+	for ; g != nil; g = g.next {
+		for k, member := range g.keys {
+			if !seen[k] {
+				seen[k] = true
+				if member {
+					fn(k)
+				}
+			}
+		}
+		if g.done {
+			m = g.frozen
+			break
+		}
+	}
+	for k := range m {
+		if !seen[k] {
+			fn(k)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package cow
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// write sets s[i] to v, saving its page first.
+func write(l *Log[int], s []int, i, v int) {
+	l.Touch(s, i, i)
+	s[i] = v
+}
+
+func TestLog(t *testing.T) {
+	var l Log[int]
+	s := make([]int, 2*PageSize)
+
+	// nothing's saved without a snapshot
+	write(&l, s, 0, 1)
+	assert.False(t, l.Active())
+
+	g1 := l.Snapshot()
+	assert.Same(t, g1, l.Snapshot(), "consecutive snapshots share a generation")
+	write(&l, s, 0, 2)
+	write(&l, s, 1, 3) // the page is only saved once
+	g2 := l.Snapshot()
+	assert.NotSame(t, g1, g2)
+	write(&l, s, 0, 4)
+	write(&l, s, PageSize, 5)
+
+	assert.Equal(t, []int{1, 0}, g1.Page(s, 0)[:2])
+	assert.Equal(t, []int{2, 3}, g2.Page(s, 0)[:2], "found in the next generation")
+	assert.Equal(t, 0, g1.Page(s, 1)[0], "saved by g2 for g1 too")
+	assert.Equal(t, []int{4, 3}, (*Gen[int])(nil).Page(s, 0)[:2], "live")
+
+	// a page beyond the end
+	assert.Empty(t, g1.Page(s, 2))
+}
+
+func TestLogFreeze(t *testing.T) {
+	var l Log[int]
+	s := make([]int, 10)
+	s[3] = 1
+
+	g := l.Snapshot()
+	l.Freeze(s)
+	assert.False(t, l.Active())
+
+	// the owner moves on to a new slice, which isn't saved
+	next := make([]int, 20)
+	write(&l, next, 3, 2)
+	assert.Equal(t, 1, g.Page(next, 0)[3])
+	assert.Len(t, g.Page(next, 0), 10, "the frozen slice's length")
+
+	// releasing a snapshot of the frozen slice doesn't affect later ones
+	h := l.Snapshot()
+	l.Release(g)
+	assert.True(t, l.Active())
+	write(&l, next, 3, 4)
+	assert.Equal(t, 2, h.Page(next, 0)[3])
+}
+
+func TestLogRelease(t *testing.T) {
+	var l Log[int]
+	s := make([]int, 10)
+
+	g1 := l.Snapshot()
+	shared := l.Snapshot()
+	write(&l, s, 0, 1)
+	g2 := l.Snapshot()
+
+	l.Release(g2)
+	l.Release(g1)
+	assert.True(t, l.Active(), "a snapshot is left")
+	l.Release(shared)
+	assert.False(t, l.Active())
+
+	write(&l, s, 0, 2)
+	g3 := l.Snapshot()
+	assert.Empty(t, g3.pages)
+	assert.Equal(t, 2, g3.Page(s, 0)[0])
+}
+
+func TestKeyLog(t *testing.T) {
+	var l KeyLog[int, struct{}]
+	m := map[int]struct{}{1: {}, 2: {}}
+
+	add := func(k int) {
+		l.Touch(k, contains(m, k))
+		m[k] = struct{}{}
+	}
+	remove := func(k int) {
+		l.Touch(k, contains(m, k))
+		delete(m, k)
+	}
+
+	g1 := l.Snapshot()
+	add(3)
+	remove(1)
+	g2 := l.Snapshot()
+	remove(2)
+	add(1)
+
+	assert.True(t, g1.Member(m, 1))
+	assert.False(t, g1.Member(m, 3))
+	assert.True(t, g2.Member(m, 2))
+	assert.Equal(t, []int{1, 2}, keys(g1, m))
+	assert.Equal(t, []int{2, 3}, keys(g2, m))
+	assert.Equal(t, []int{1, 3}, keys(nil, m))
+
+	l.Freeze(m)
+	m = map[int]struct{}{}
+	assert.False(t, l.Active())
+	assert.Equal(t, []int{1, 2}, keys(g1, m))
+	assert.Equal(t, []int{2, 3}, keys(g2, m), "3 from the frozen map")
+
+	g3 := l.Snapshot()
+	l.Release(g1)
+	l.Release(g2)
+	assert.True(t, l.Active())
+	l.Release(g3)
+	assert.False(t, l.Active())
+}
+
+func contains(m map[int]struct{}, k int) bool {
+	_, ok := m[k]
+	return ok
+}
+
+func keys(g *KeyGen[int, struct{}], m map[int]struct{}) []int {
+	var ks []int
+	g.Keys(m, func(k int) { ks = append(ks, k) })
+	sort.Ints(ks)
+	return ks
+}
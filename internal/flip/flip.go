@@ -0,0 +1,30 @@
+// Package flip finds the values which a modification would actually change, for the wrappers which report or
+// journal them.
+package flip
+
+import (
+	"sort"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Changed returns the distinct indices whose membership, as reported by get, is currently member, in ascending order.
+// They're the values which unsetting, when member is true, or setting, when it's false, would flip.
+func Changed[V bitset.Value](get func(V) bool, indices []V, member bool) []V {
+	var changed []V
+	for _, index := range indices {
+		if get(index) == member {
+			changed = append(changed, index)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+
+	n := 0
+	for i, v := range changed {
+		if i == 0 || v != changed[n-1] {
+			changed[n] = v
+			n++
+		}
+	}
+	return changed[:n]
+}
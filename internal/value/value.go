@@ -0,0 +1,17 @@
+// Package value holds helpers for the bounds of bitset values.
+package value
+
+import (
+	"math"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Max is the largest V.
+func Max[V bitset.Value]() V {
+	if m := ^V(0); m > 0 {
+		return m
+	}
+	m := int64(math.MaxInt32) // ~rune is the only signed Value
+	return V(m)
+}
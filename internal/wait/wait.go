@@ -0,0 +1,81 @@
+// Package wait lets goroutines block until members are added to a set.
+//
+// A Signal is a channel which is closed, and replaced, whenever the set gains members. Waiters check their condition
+// and take the channel under the set's lock, then wait on it without, so a member added in between is never missed.
+// Nothing is started to wait on their behalf, so a cancelled waiter leaves nothing behind.
+package wait
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Signal wakes waiters. Its zero value is ready for use, and it's guarded by its owner's write lock.
+type Signal struct {
+	ch chan struct{}
+}
+
+// Notify wakes every waiter, after members are added. It's cheap when there are none.
+func (s *Signal) Notify() {
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+}
+
+// wait returns the channel which the next Notify closes.
+func (s *Signal) wait() <-chan struct{} {
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+// For blocks until ready, which is called with lock held, reports true, or ctx is done.
+// If ready already does, it returns nil even if ctx is done.
+func For(ctx context.Context, lock sync.Locker, s *Signal, ready func() bool) error {
+	for {
+		lock.Lock()
+		if ready() {
+			lock.Unlock()
+			return nil
+		}
+		ch := s.wait()
+		lock.Unlock()
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// All blocks until get reports every one of vs at once, or ctx is done.
+func All[V bitset.Value](ctx context.Context, lock sync.Locker, s *Signal, get func(V) bool, vs []V) error {
+	return For(ctx, lock, s, func() bool {
+		for _, v := range vs {
+			if !get(v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Any blocks until get reports one of vs, which is returned, or ctx is done. With no vs, it waits for ctx.
+func Any[V bitset.Value](ctx context.Context, lock sync.Locker, s *Signal, get func(V) bool, vs []V) (V, error) {
+	var found V
+	err := For(ctx, lock, s, func() bool {
+		for _, v := range vs {
+			if get(v) {
+				found = v
+				return true
+			}
+		}
+		return false
+	})
+	return found, err
+}
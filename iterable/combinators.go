@@ -0,0 +1,177 @@
+package iterable
+
+import (
+	"sync"
+
+	"github.com/zblach/go-bitset"
+)
+
+// funcIter adapts a generator function into an Iter.
+type funcIter[V any] struct {
+	lock *sync.RWMutex
+	next func() (V, bool)
+}
+
+func (it *funcIter[V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	return it.next()
+}
+
+// funcIterable adapts an iterator constructor into an Iterable.
+type funcIterable[V bitset.Value] func() (Iter[V], uint)
+
+func (f funcIterable[V]) Iterate() (Iter[V], uint) {
+	return f()
+}
+
+func newFuncIter[V any](next func() (V, bool)) *funcIter[V] {
+	return &funcIter[V]{lock: &sync.RWMutex{}, next: next}
+}
+
+// Filter contains the values of s for which keep returns true.
+func Filter[V bitset.Value](s Iterable[V], keep func(V) bool) Iterable[V] {
+	return funcIterable[V](func() (Iter[V], uint) {
+		it, size := s.Iterate()
+
+		return newFuncIter(func() (V, bool) {
+			for v, ok := it.Next(); ok; v, ok = it.Next() {
+				if keep(v) {
+					return v, true
+				}
+			}
+			var _v V
+			return _v, false
+		}), size
+	})
+}
+
+// Shift offsets every value of s by delta. Values which would fall outside of the range of V are dropped.
+// s may iterate in any order.
+func Shift[V bitset.Value](s Iterable[V], delta int64) Iterable[V] {
+	return funcIterable[V](func() (Iter[V], uint) {
+		it, size := s.Iterate()
+
+		return newFuncIter(func() (V, bool) {
+			var _v V
+			for v, ok := it.Next(); ok; v, ok = it.Next() {
+				u := uint64(v)
+				var x uint64
+				if delta >= 0 {
+					x = u + uint64(delta)
+					if x < u {
+						continue
+					}
+				} else {
+					d := uint64(-(delta + 1)) + 1 // avoids overflowing on math.MinInt64
+					if u < d {
+						continue
+					}
+					x = u - d
+				}
+
+				if uint64(V(x)) != x {
+					// too large to be represented as V.
+					continue
+				}
+				return V(x), true
+			}
+			return _v, false
+		}), size
+	})
+}
+
+// Take contains the first n values of s.
+func Take[V bitset.Value](s Iterable[V], n uint) Iterable[V] {
+	return funcIterable[V](func() (Iter[V], uint) {
+		it, size := s.Iterate()
+		if n < size {
+			size = n
+		}
+
+		remaining := n
+		return newFuncIter(func() (V, bool) {
+			if remaining == 0 {
+				var _v V
+				return _v, false
+			}
+			remaining--
+			return it.Next()
+		}), size
+	})
+}
+
+// Skip contains all but the first n values of s.
+func Skip[V bitset.Value](s Iterable[V], n uint) Iterable[V] {
+	return funcIterable[V](func() (Iter[V], uint) {
+		it, size := s.Iterate()
+		if n < size {
+			size -= n
+		} else {
+			size = 0
+		}
+
+		skip := n
+		return newFuncIter(func() (V, bool) {
+			for ; skip > 0; skip-- {
+				if _, ok := it.Next(); !ok {
+					skip = 0
+					var _v V
+					return _v, false
+				}
+			}
+			return it.Next()
+		}), size
+	})
+}
+
+// Window contains the values of s between lo and hi, inclusive. s must iterate in ascending order.
+func Window[V bitset.Value](s Iterable[V], lo, hi V) Iterable[V] {
+	return funcIterable[V](func() (Iter[V], uint) {
+		it, size := s.Iterate()
+
+		done := false
+		return newFuncIter(func() (V, bool) {
+			var _v V
+			if done {
+				return _v, false
+			}
+			for v, ok := it.Next(); ok; v, ok = it.Next() {
+				if v > hi {
+					break
+				}
+				if v >= lo {
+					return v, true
+				}
+			}
+			done = true
+			return _v, false
+		}), size
+	})
+}
+
+// Chunk batches the values of s into slices of up to n values. Only the final chunk may be shorter than n.
+func Chunk[V bitset.Value](s Iterable[V], n int) Iter[[]V] {
+	if n <= 0 {
+		panic("iterable: chunk size must be positive")
+	}
+
+	it, _ := s.Iterate()
+	return newFuncIter(func() ([]V, bool) {
+		chunk := make([]V, 0, n)
+		for len(chunk) < n {
+			v, ok := it.Next()
+			if !ok {
+				break
+			}
+			chunk = append(chunk, v)
+		}
+		return chunk, len(chunk) > 0
+	})
+}
+
+var (
+	_ Iter[[]uint]   = (*funcIter[[]uint])(nil)
+	_ Iterable[rune] = (funcIterable[rune])(nil)
+)
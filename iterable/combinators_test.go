@@ -0,0 +1,56 @@
+package iterable_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+func Test_Combinators(t *testing.T) {
+	s := bits.New[uint8, uint8](0)
+	s.Set(1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144, 233)
+
+	odd := iterable.Filter[uint8](s, func(v uint8) bool { return v%2 == 1 })
+	assert.EqualValues(t, []uint8{1, 3, 5, 13, 21, 55, 89, 233}, iterable.Values(odd))
+
+	assert.EqualValues(t, []uint8{11, 12, 13, 15, 18, 23, 31, 44, 65, 99, 154, 243}, iterable.Values(iterable.Shift[uint8](s, 10)))
+	assert.EqualValues(t, []uint8{3, 11, 24, 45, 79, 134, 223}, iterable.Values(iterable.Shift[uint8](s, -10)))
+	assert.EqualValues(t, []uint8{101, 102, 103, 105, 108, 113, 121, 134, 155, 189, 244}, iterable.Values(iterable.Shift[uint8](s, 100)))
+	assert.Empty(t, iterable.Values(iterable.Shift[uint8](s, math.MinInt64)))
+	assert.Empty(t, iterable.Values(iterable.Shift[uint8](s, math.MaxInt64)))
+	assert.EqualValues(t, []uint8{244, 189, 155, 134, 121, 113, 108, 105, 103, 102, 101}, iterable.Values(iterable.Shift(iterable.Reverse[uint8](s), 100)))
+
+	it, size := iterable.Take[uint8](s, 3).Iterate()
+	assert.EqualValues(t, 3, size)
+	v, _ := it.Next()
+	assert.EqualValues(t, 1, v)
+	assert.EqualValues(t, []uint8{1, 2, 3}, iterable.Values(iterable.Take[uint8](s, 3)))
+	assert.EqualValues(t, []uint8{144, 233}, iterable.Values(iterable.Skip[uint8](s, 10)))
+	assert.Empty(t, iterable.Values(iterable.Skip[uint8](s, 100)))
+
+	assert.EqualValues(t, []uint8{5, 8, 13}, iterable.Values(iterable.Window[uint8](s, 4, 13)))
+
+	// pagination
+	page := iterable.Take(iterable.Skip[uint8](iterable.Window[uint8](s, 2, 100), 3), 3)
+	assert.EqualValues(t, []uint8{8, 13, 21}, iterable.Values(page))
+}
+
+func Test_Chunk(t *testing.T) {
+	s := bits.NewUint(0)
+	s.Set(1, 2, 3, 4, 5, 6, 7)
+
+	var chunks [][]uint
+	it := iterable.Chunk[uint](s, 3)
+	for c, ok := it.Next(); ok; c, ok = it.Next() {
+		chunks = append(chunks, c)
+	}
+	assert.Equal(t, [][]uint{{1, 2, 3}, {4, 5, 6}, {7}}, chunks)
+
+	_, ok := iterable.Chunk[uint](bits.NewUint(0), 3).Next()
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { iterable.Chunk[uint](s, 0) })
+}
@@ -13,7 +13,8 @@ type Iterable[V bitset.Value] interface {
 }
 
 // experimental. see: https://github.com/golang/go/discussions/54245. think python generators.
-type Iter[V bitset.Value] interface {
+// Iter isn't limited to bitset values, so that iterators can yield derived values, like chunks.
+type Iter[V any] interface {
 	Next() (V, bool)
 }
 
@@ -29,29 +30,56 @@ func Values[V bitset.Value](s Iterable[V]) (vals []V) {
 	return
 }
 
+// copyBatch is the number of values Copy hands to Set at a time.
+const copyBatch = 4096
+
 // Copy copies the values from `src` into `dst`, clearing the contents of `dst` beforehand.
 // These arguments need to track the same kind of value, but do not need to be the same implementation.
+// Values are streamed into dst in batches, rather than collected up front.
 func Copy[V bitset.Value](dst bitset.Bitset[V], src Iterable[V]) {
 	dst.Clear()
-	dst.Set(Values(src)...)
+
+	it, _ := src.Iterate()
+	batch := make([]V, 0, copyBatch)
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		batch = append(batch, v)
+		if len(batch) == cap(batch) {
+			dst.Set(batch...)
+			batch = batch[:0]
+		}
+	}
+	dst.Set(batch...)
 }
 
 // wrapper structs for heterogenous AND/OR operations
 type groupIterator[V bitset.Value] struct {
 	iters []Iterable[V]
+	desc  bool // iters are in descending order
 }
 type (
 	andIterator[V bitset.Value] groupIterator[V]
 	orIterator[V bitset.Value]  groupIterator[V]
+	xorIterator[V bitset.Value] groupIterator[V]
 )
 
 type groupIter[V bitset.Value] struct {
 	lock  *sync.RWMutex
 	iters []*peekIter[V]
+	desc  bool
 }
+
+// before reports whether a comes before b, in the direction of iteration.
+func before[V bitset.Value](desc bool, a, b V) bool {
+	if desc {
+		return a > b
+	}
+	return a < b
+}
+
 type (
 	andIter[V bitset.Value] groupIter[V]
 	orIter[V bitset.Value]  groupIter[V]
+	xorIter[V bitset.Value] groupIter[V]
 )
 
 // an Iter[V], which remembers the last value read
@@ -71,6 +99,8 @@ func (it *peekIter[V]) Next() (V, bool) {
 var (
 	_ Iter[byte]   = (*andIter[byte])(nil)
 	_ Iter[uint16] = (*orIter[uint16])(nil)
+	_ Iter[uint32] = (*xorIter[uint32])(nil)
+	_ Iter[uint]   = (*andNotIter[uint])(nil)
 )
 
 // Next implements Iter for all 's'
@@ -87,9 +117,9 @@ func (and *andIter[V]) Next() (V, bool) {
 		// find minimum value
 		minIndex, maxIndex := 0, 0
 		for i, it := range and.iters[1:] {
-			if and.iters[minIndex].curr > it.curr {
+			if before(and.desc, it.curr, and.iters[minIndex].curr) {
 				minIndex = i + 1
-			} else if and.iters[maxIndex].curr < it.curr {
+			} else if before(and.desc, and.iters[maxIndex].curr, it.curr) {
 				maxIndex = i + 1
 			}
 		}
@@ -137,7 +167,7 @@ func (or *orIter[V]) Next() (V, bool) {
 	// find the lowest value
 	minIndex := 0
 	for i := 1; i < len(or.iters); i++ {
-		if or.iters[minIndex].curr > or.iters[i].curr {
+		if before(or.desc, or.iters[i].curr, or.iters[minIndex].curr) {
 			minIndex = i
 		}
 	}
@@ -160,6 +190,84 @@ func (or *orIter[V]) Next() (V, bool) {
 	return ret, true
 }
 
+func (xor *xorIter[V]) Next() (V, bool) {
+	xor.lock.Lock()
+	defer xor.lock.Unlock()
+
+	var _v V
+	for len(xor.iters) > 0 {
+		// find the lowest value
+		minIndex := 0
+		for i := 1; i < len(xor.iters); i++ {
+			if before(xor.desc, xor.iters[i].curr, xor.iters[minIndex].curr) {
+				minIndex = i
+			}
+		}
+
+		ret := xor.iters[minIndex].curr
+		// increment all iterators with that same value, counting how many held it
+		count := 0
+		nextIters := make([]*peekIter[V], 0, len(xor.iters))
+		for _, it := range xor.iters {
+			if it.curr != ret {
+				nextIters = append(nextIters, it)
+				continue
+			}
+			count++
+			if _, ok := it.Next(); ok {
+				nextIters = append(nextIters, it)
+			}
+		}
+		xor.iters = nextIters
+
+		if count%2 == 1 {
+			return ret, true
+		}
+	}
+
+	return _v, false
+}
+
+// andNotIter yields the values of src which are not present in any of the excluded iterators.
+type andNotIter[V bitset.Value] struct {
+	lock    *sync.RWMutex
+	src     Iter[V]
+	exclude []*peekIter[V]
+}
+
+func (an *andNotIter[V]) Next() (V, bool) {
+	an.lock.Lock()
+	defer an.lock.Unlock()
+
+	for v, ok := an.src.Next(); ok; v, ok = an.src.Next() {
+		excluded := false
+		nextIters := an.exclude[:0]
+		for _, it := range an.exclude {
+			// catch up to the candidate value
+			live := true
+			for live && it.curr < v {
+				_, live = it.Next()
+			}
+			if !live {
+				// exhausted iterators can't exclude anything else
+				continue
+			}
+			if it.curr == v {
+				excluded = true
+			}
+			nextIters = append(nextIters, it)
+		}
+		an.exclude = nextIters
+
+		if !excluded {
+			return v, true
+		}
+	}
+
+	var _v V
+	return _v, false
+}
+
 func And[V bitset.Value](s1, s2 Iterable[V], s ...Iterable[V]) Iterable[V] {
 	return andIterator[V]{iters: append(s[:], s1, s2)}
 }
@@ -168,9 +276,25 @@ func Or[V bitset.Value](s1, s2 Iterable[V], s ...Iterable[V]) Iterable[V] {
 	return orIterator[V]{iters: append(s[:], s1, s2)}
 }
 
+// Xor is the symmetric difference of all sets. Values are included if they are present in an odd number of sets.
+func Xor[V bitset.Value](s1, s2 Iterable[V], s ...Iterable[V]) Iterable[V] {
+	return xorIterator[V]{iters: append(s[:], s1, s2)}
+}
+
+// AndNot contains the values of s which are not present in any of the excluded sets.
+func AndNot[V bitset.Value](s Iterable[V], exclude ...Iterable[V]) Iterable[V] {
+	return andNotIterator[V]{src: s, exclude: exclude}
+}
+
+type andNotIterator[V bitset.Value] struct {
+	src     Iterable[V]
+	exclude []Iterable[V]
+}
+
 // Iterate implements Iterable
 func (ai andIterator[V]) Iterate() (Iter[V], uint) {
 	gi, min, _ := newIter(ai.iters...)
+	gi.desc = ai.desc
 	if min == 0 {
 		// then one of the iterators is empty. this means no values at all.
 		gi.iters = nil
@@ -181,12 +305,36 @@ func (ai andIterator[V]) Iterate() (Iter[V], uint) {
 
 func (oi orIterator[V]) Iterate() (Iter[V], uint) {
 	gi, _, max := newIter(oi.iters...)
+	gi.desc = oi.desc
 	it := orIter[V](gi)
 	return &it, max
 
 }
 
-var _ Iterable[rune] = (*andIterator[rune])(nil)
+func (xi xorIterator[V]) Iterate() (Iter[V], uint) {
+	gi, _, max := newIter(xi.iters...)
+	gi.desc = xi.desc
+	it := xorIter[V](gi)
+	return &it, max
+}
+
+func (ani andNotIterator[V]) Iterate() (Iter[V], uint) {
+	src, size := ani.src.Iterate()
+	gi, _, _ := newIter(ani.exclude...)
+
+	return &andNotIter[V]{
+		lock:    gi.lock,
+		src:     src,
+		exclude: gi.iters,
+	}, size
+}
+
+var (
+	_ Iterable[rune] = (*andIterator[rune])(nil)
+	_ Iterable[rune] = (*orIterator[rune])(nil)
+	_ Iterable[rune] = (*xorIterator[rune])(nil)
+	_ Iterable[rune] = (*andNotIterator[rune])(nil)
+)
 
 // newIter is an internal utility function for iterator composition. the return values are
 // gi - groupIter -- a 'Next()'-able object to query the next value from
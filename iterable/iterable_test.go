@@ -1,12 +1,16 @@
 package iterable_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
 	"github.com/zblach/go-bitset/dense/bits"
 	"github.com/zblach/go-bitset/dense/bools"
+	"github.com/zblach/go-bitset/internal/backends"
 	"github.com/zblach/go-bitset/iterable"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
 )
 
 func Test_AndIterable(t *testing.T) {
@@ -50,3 +54,153 @@ func Test_HeterogenousCopy(t *testing.T) {
 	assert.Equal(t, dst.Pop(), uint(8))
 
 }
+
+func Test_XorIterable(t *testing.T) {
+	a := bits.New[uint8, rune](0)
+	a.Set(1, 2, 4, 8)
+
+	b := bits.New[uint32, rune](0)
+	b.Set(2, 4, 6)
+
+	c := bits.New[uint16, rune](0)
+	c.Set(2, 3, 6)
+
+	xorIter := iterable.Xor[rune](a, b, c)
+
+	assert.EqualValues(t, []rune{1, 2, 3, 8}, iterable.Values(xorIter))
+}
+
+func Test_AndNotIterable(t *testing.T) {
+	a := bits.New[uint8, rune](0)
+	a.Set(1, 2, 4, 8, 16, 22)
+
+	b := bits.New[uint32, rune](0)
+	b.Set(2, 6, 22)
+
+	c := bools.New[rune](0)
+	c.Set(0, 8)
+
+	andNotIter := iterable.AndNot[rune](a, b, c)
+
+	assert.EqualValues(t, []rune{1, 4, 16}, iterable.Values(andNotIter))
+	assert.EqualValues(t, []rune{1, 2, 4, 8, 16, 22}, iterable.Values(iterable.AndNot[rune](a)))
+}
+
+func Test_ReverseIterable(t *testing.T) {
+	a := bits.New[uint8, rune](0)
+	a.Set(1, 2, 4, 8, 16, 22)
+
+	b := bits.New[uint32, rune](0)
+	b.Set(2, 4, 6, 8, 10, 22)
+
+	c := bools.New[rune](0)
+	c.Set(1, 2, 3, 5, 8, 11, 13, 22)
+
+	assert.EqualValues(t, []rune{22, 8, 2}, iterable.Values(iterable.Reverse(iterable.AndReverse[rune](a, b, c))))
+	assert.EqualValues(t, []rune{22, 16, 13, 11, 10, 8, 6, 5, 4, 3, 2, 1}, iterable.Values(iterable.Reverse(iterable.OrReverse[rune](a, b, c))))
+
+	// the most recent 3
+	assert.EqualValues(t, []rune{22, 16, 13}, iterable.Values(iterable.Take(iterable.Reverse(iterable.OrReverse[rune](a, b, c)), 3)))
+}
+
+func runs[V bitset.Value](it iterable.Iter[iterable.Run[V]]) (rs []iterable.Run[V]) {
+	for r, ok := it.Next(); ok; r, ok = it.Next() {
+		rs = append(rs, r)
+	}
+	return
+}
+
+func Test_Runnable(t *testing.T) {
+	wantRuns := []iterable.Run[uint8]{
+		{Start: 0, End: 0, Set: false},
+		{Start: 1, End: 2, Set: true},
+		{Start: 3, End: 7, Set: false},
+		{Start: 8, End: 8, Set: true},
+		{Start: 9, End: 63, Set: false},
+		{Start: 64, End: 130, Set: true},
+		{Start: 131, End: 254, Set: false},
+		{Start: 255, End: 255, Set: true},
+	}
+	members := []uint8{1, 2, 8, 255}
+	for i := uint8(64); i <= 130; i++ {
+		members = append(members, i)
+	}
+
+	for name, mk := range backends.WithFixed[uint8]() {
+		s, ok := mk().(interface {
+			bitset.Bitset[uint8]
+			iterable.Iterable[uint8]
+			iterable.Runnable[uint8]
+		})
+		if !ok {
+			continue
+		}
+		s.Set(members...)
+
+		assert.Equal(t, wantRuns, runs(s.Runs()), name)
+		assert.Equal(t, wantRuns, runs(iterable.RunsOf[uint8](s)), name)
+
+		it, size := s.IterateClear(10)
+		assert.GreaterOrEqual(t, size, uint(8), name)
+		clear := []uint8{}
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			clear = append(clear, v)
+		}
+		assert.Equal(t, []uint8{0, 3, 4, 5, 6, 7, 9, 10}, clear, name)
+
+		it, _ = s.IterateClear(255)
+		n := 0
+		for _, ok := it.Next(); ok; _, ok = it.Next() {
+			n++
+		}
+		assert.Equal(t, 256-len(members), n, name)
+
+		assert.Equal(t, []uint8{63, 131}, iterable.Values(iterable.Take(iterable.Skip(iterable.Complement[uint8](s, 255), 60), 2)), name)
+	}
+
+	assert.Empty(t, runs(bits.NewUint(100).Runs()))
+	assert.Empty(t, runs(rangeset.New[uint]().Runs()))
+}
+
+func Test_Format(t *testing.T) {
+	for name, mk := range backends.WithFixed[uint8]() {
+		s := mk().(interface {
+			bitset.Bitset[uint8]
+			fmt.Formatter
+			fmt.Stringer
+		})
+		s.Set(1, 3, 5, 6, 7, 8, 9, 200)
+
+		assert.Equal(t, "{1, 3, 5-9, 200}", s.String(), name)
+		assert.Equal(t, "{1, 3, 5-9, 200}", fmt.Sprint(s), name)
+		assert.Equal(t, "{1, 3, 5, 6, … +4 more}", fmt.Sprintf("%.4d", s), name)
+		assert.Equal(t, "0x1000000000000000000000000000000000000000000000003ea", fmt.Sprintf("%#x", s), name)
+		assert.Contains(t, fmt.Sprintf("%+v", s), "{1, 3, 5-9, 200} (pop 8, len ", name)
+	}
+}
+
+func Test_Stats(t *testing.T) {
+	for name, mk := range backends.WithFixed[uint8]() {
+		s := mk().(interface {
+			bitset.Bitset[uint8]
+			bitset.Inspect[uint8]
+		})
+		empty := s.Bytes()
+		assert.Equal(t, bitset.Stats[uint8]{Bytes: empty}, s.Stats(), name)
+
+		s.Set(1, 3, 5, 6, 7, 8, 9, 200)
+		st := s.Stats()
+		assert.Equal(t, s.Bytes(), st.Bytes, name)
+		assert.GreaterOrEqual(t, st.Bytes, empty, name)
+		assert.Equal(t, bitset.Stats[uint8]{
+			Pop:           8,
+			Bytes:         st.Bytes,
+			Min:           1,
+			Max:           200,
+			Runs:          4,
+			Words:         2,
+			Density:       8.0 / 200,
+			Fragmentation: 3.0 / 7,
+		}, st, name)
+	}
+}
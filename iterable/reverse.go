@@ -0,0 +1,56 @@
+package iterable
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Reversible sets can be enumerated in descending order.
+type Reversible[V bitset.Value] interface {
+	IterateReverse() (it Iter[V], size uint)
+}
+
+// Reverse is an Iterable which enumerates s in descending order.
+// It can be used anywhere an Iterable is, e.g. Take(Reverse(s), 10) for the 10 largest values.
+func Reverse[V bitset.Value](s Reversible[V]) Iterable[V] {
+	return reverse[V]{s}
+}
+
+type reverse[V bitset.Value] struct {
+	Reversible[V]
+}
+
+func (r reverse[V]) Iterate() (Iter[V], uint) {
+	return r.IterateReverse()
+}
+
+// reversed adapts a descending Iterable back into a Reversible.
+type reversed[V bitset.Value] struct {
+	Iterable[V]
+}
+
+func (r reversed[V]) IterateReverse() (Iter[V], uint) {
+	return r.Iterate()
+}
+
+func descending[V bitset.Value](s []Reversible[V]) []Iterable[V] {
+	iters := make([]Iterable[V], len(s))
+	for i, r := range s {
+		iters[i] = Reverse(r)
+	}
+	return iters
+}
+
+// AndReverse is the intersection of all sets, enumerated in descending order.
+func AndReverse[V bitset.Value](s1, s2 Reversible[V], s ...Reversible[V]) Reversible[V] {
+	return reversed[V]{andIterator[V]{iters: descending(append(s[:], s1, s2)), desc: true}}
+}
+
+// OrReverse is the union of all sets, enumerated in descending order.
+func OrReverse[V bitset.Value](s1, s2 Reversible[V], s ...Reversible[V]) Reversible[V] {
+	return reversed[V]{orIterator[V]{iters: descending(append(s[:], s1, s2)), desc: true}}
+}
+
+var (
+	_ Iterable[uint]   = reverse[uint]{}
+	_ Reversible[rune] = reversed[rune]{}
+)
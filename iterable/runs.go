@@ -0,0 +1,117 @@
+package iterable
+
+import (
+	"fmt"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Run is a span of consecutive positions which are either all set, or all unset. Start and End are inclusive.
+type Run[V bitset.Value] struct {
+	Start, End V
+	Set        bool
+}
+
+// Runnable sets can enumerate their unset positions, and summarize themselves as runs.
+type Runnable[V bitset.Value] interface {
+	// IterateClear enumerates the unset positions from 0 up to and including limit.
+	IterateClear(limit V) (it Iter[V], size uint)
+
+	// Runs enumerates alternating unset and set runs, in ascending order, from 0 through the largest member.
+	// An empty set has no runs.
+	Runs() Iter[Run[V]]
+}
+
+// Complement contains the values from 0 up to and including limit which are not in s. s must iterate in ascending order.
+func Complement[V bitset.Value](s Iterable[V], limit V) Iterable[V] {
+	return funcIterable[V](func() (Iter[V], uint) {
+		it, _ := s.Iterate()
+		member, ok := it.Next()
+
+		var pos V
+		done := false
+		return newFuncIter(func() (V, bool) {
+			for !done {
+				for ok && member < pos {
+					member, ok = it.Next()
+				}
+
+				ret := pos
+				if ret == limit {
+					done = true
+				} else {
+					pos++
+				}
+				if !ok || member != ret {
+					return ret, true
+				}
+			}
+
+			var _v V
+			return _v, false
+		}), sizeTo(limit)
+	})
+}
+
+// RunsOf summarizes s as runs. s must iterate in ascending order.
+func RunsOf[V bitset.Value](s Iterable[V]) Iter[Run[V]] {
+	it, _ := s.Iterate()
+	member, ok := it.Next()
+
+	var pos V
+	return newFuncIter(func() (Run[V], bool) {
+		if !ok {
+			return Run[V]{}, false
+		}
+
+		if pos < member {
+			r := Run[V]{Start: pos, End: member - 1}
+			pos = member
+			return r, true
+		}
+
+		r := Run[V]{Start: member, End: member, Set: true}
+		for member, ok = it.Next(); ok && member == r.End+1; member, ok = it.Next() {
+			r.End = member
+		}
+		pos = r.End + 1
+		return r, true
+	})
+}
+
+// sizeTo is the number of positions from 0 up to and including limit, saturating.
+func sizeTo[V bitset.Value](limit V) uint {
+	if uint(limit)+1 == 0 {
+		return uint(limit)
+	}
+	return uint(limit) + 1
+}
+
+var _ Iter[Run[uint]] = (*funcIter[Run[uint]])(nil)
+
+// Measure computes bitset.Stats from runs, such as those of a Runnable. bytes is the set's Bytes.
+func Measure[V bitset.Value](bytes int, runs Iter[Run[V]]) bitset.Stats[V] {
+	return bitset.MeasureRuns(bytes, members(runs))
+}
+
+// String renders a set from its runs, such as those of a Runnable. See bitset.String.
+func String[V bitset.Value](runs Iter[Run[V]]) string {
+	return bitset.StringRuns(members(runs))
+}
+
+// Format implements fmt.Formatter on behalf of a set, from its runs, such as those of a Runnable. See bitset.Format.
+func Format[V bitset.Value](f fmt.State, verb rune, runs Iter[Run[V]], info bitset.Inspect[V]) {
+	bitset.FormatRuns(f, verb, members(runs), info)
+}
+
+// members returns the set runs from runs, in the form bitset's run functions take.
+func members[V bitset.Value](runs Iter[Run[V]]) func() (V, V, bool) {
+	return func() (V, V, bool) {
+		for r, ok := runs.Next(); ok; r, ok = runs.Next() {
+			if r.Set {
+				return r.Start, r.End, true
+			}
+		}
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,308 @@
+package ops
+
+import (
+	"math"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/convert"
+	"github.com/zblach/go-bitset/internal/value"
+	"github.com/zblach/go-bitset/iterable"
+	mapset "github.com/zblach/go-bitset/sparse/map"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+// dense is implemented by backends which can skip to their next member, like bits and hier.
+type dense[V bitset.Value] interface {
+	iterable.Iterable[V]
+	NextSet(from V) (V, bool)
+}
+
+// getter is implemented by every backend.
+type getter[V bitset.Value] interface {
+	iterable.Iterable[V]
+	Get(index V) bool
+}
+
+// ranger is implemented by backends which can set a range of values at once.
+type ranger[V bitset.Value] interface {
+	SetRange(lo, hi V)
+}
+
+// masker is implemented by bits, which can copy the members of another bits.Bitset within a range, a word at a time.
+type masker[V bitset.Value] interface {
+	OrMasked(src any, lo, hi V) bool
+}
+
+// builtin returns the kernel for op on a and b, in that order, or nil if there isn't one.
+func builtin[V bitset.Value](op Op, a, b iterable.Iterable[V]) Kernel[V] {
+	ra, aRanges := a.(*rangeset.Bitset[V])
+	rb, bRanges := b.(*rangeset.Bitset[V])
+	da, aDense := a.(dense[V])
+	db, bDense := b.(dense[V])
+	ga, aGets := a.(getter[V])
+	gb, bGets := b.(getter[V])
+	_, aMap := a.(*mapset.Bitset[V])
+	mb, bMap := b.(*mapset.Bitset[V])
+
+	switch {
+	case aRanges && bRanges:
+		return func(dst bitset.Bitset[V], _, _ iterable.Iterable[V]) { rangesRanges(op, dst, ra, rb) }
+	case aDense && bRanges:
+		return denseRanges(op, da, rb)
+	case aRanges && bDense && op == OpAndNot:
+		return func(dst bitset.Bitset[V], _, _ iterable.Iterable[V]) { rangesMinusDense(dst, ra, db) }
+	case aGets && bMap:
+		return func(dst bitset.Bitset[V], _, _ iterable.Iterable[V]) { anyMap(op, dst, ga, mb) }
+	case aMap && bGets && op == OpAndNot:
+		return func(dst bitset.Bitset[V], a, _ iterable.Iterable[V]) {
+			filter(dst, a, func(v V) bool { return !gb.Get(v) })
+		}
+	}
+	return nil
+}
+
+// rangesRanges sweeps over the ranges of both operands at once, so the cost is in the number of ranges.
+func rangesRanges[V bitset.Value](op Op, dst bitset.Bitset[V], a, b *rangeset.Bitset[V]) {
+	w := newWriter(dst)
+	sweep(spans(a), spans(b), op.keep, func(lo, hi uint64) { w.span(V(lo), V(hi)) })
+	w.flush()
+}
+
+// denseRanges returns the kernel for a dense a and ranges b, or nil for xor.
+// Members of a are copied a range at a time, by masked words when dst and a are both bits.
+func denseRanges[V bitset.Value](op Op, a dense[V], b *rangeset.Bitset[V]) Kernel[V] {
+	switch op {
+	case OpAnd:
+		return func(dst bitset.Bitset[V], _, _ iterable.Iterable[V]) {
+			w := newWriter(dst)
+			for _, s := range spans(b) {
+				w.within(a, V(s.lo), V(s.hi))
+			}
+			w.flush()
+		}
+	case OpAndNot:
+		return func(dst bitset.Bitset[V], _, _ iterable.Iterable[V]) {
+			w, ss := newWriter(dst), spans(b)
+			next := uint64(0)
+			for _, s := range ss {
+				if s.lo > next {
+					w.within(a, V(next), V(s.lo-1))
+				}
+				next = s.hi + 1
+			}
+			if top := uint64(value.Max[V]()); len(ss) == 0 || ss[len(ss)-1].hi < top {
+				w.within(a, V(next), V(top))
+			}
+			w.flush()
+		}
+	case OpOr:
+		return func(dst bitset.Bitset[V], _, _ iterable.Iterable[V]) {
+			convert.Convert[V](dst, a)
+			w := newWriter(dst)
+			for _, s := range spans(b) {
+				w.span(V(s.lo), V(s.hi))
+			}
+			w.flush()
+		}
+	}
+	return nil
+}
+
+// rangesMinusDense writes the parts of each range of a which aren't members of b, skipping from member to member.
+func rangesMinusDense[V bitset.Value](dst bitset.Bitset[V], a *rangeset.Bitset[V], b dense[V]) {
+	w := newWriter(dst)
+	for _, s := range spans(a) {
+		pos, hi := V(s.lo), V(s.hi)
+		for {
+			n, ok := b.NextSet(pos)
+			if !ok || n > hi {
+				w.span(pos, hi)
+				break
+			}
+			if n > pos {
+				w.span(pos, n-1)
+			}
+			if n == hi {
+				break
+			}
+			pos = n + 1
+		}
+	}
+	w.flush()
+}
+
+// anyMap handles a map b, which is assumed to be the smaller operand. Its members are looked up in a,
+// which is a binary search over the ranges of a rangeset.
+func anyMap[V bitset.Value](op Op, dst bitset.Bitset[V], a getter[V], b *mapset.Bitset[V]) {
+	switch op {
+	case OpAnd:
+		filter[V](dst, b, a.Get)
+	case OpOr:
+		convert.Convert[V](dst, a)
+		w := newWriter(dst)
+		it, _ := b.Iterate()
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			w.add(v)
+		}
+		w.flush()
+	case OpAndNot:
+		convert.Convert[V](dst, a)
+		dst.Unset(iterable.Values[V](b)...)
+	case OpXor:
+		convert.Convert[V](dst, a)
+
+		var set, unset []V
+		it, _ := b.Iterate()
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			if a.Get(v) {
+				unset = append(unset, v)
+			} else {
+				set = append(set, v)
+			}
+		}
+		dst.Unset(unset...)
+		dst.Set(set...)
+	}
+}
+
+// filter writes the members of s for which keep is true.
+func filter[V bitset.Value](dst bitset.Bitset[V], s iterable.Iterable[V], keep func(V) bool) {
+	w := newWriter(dst)
+	it, _ := s.Iterate()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		if keep(v) {
+			w.add(v)
+		}
+	}
+	w.flush()
+}
+
+// stream returns the fallback kernel, which merges the operands' iterators.
+func stream[V bitset.Value](op Op) Kernel[V] {
+	return func(dst bitset.Bitset[V], a, b iterable.Iterable[V]) {
+		switch op {
+		case OpAnd:
+			iterable.Copy(dst, iterable.And(a, b))
+		case OpOr:
+			iterable.Copy(dst, iterable.Or(a, b))
+		case OpAndNot:
+			iterable.Copy(dst, iterable.AndNot(a, b))
+		case OpXor:
+			iterable.Copy(dst, iterable.Xor(a, b))
+		}
+	}
+}
+
+// span is an inclusive range of members.
+type span struct{ lo, hi uint64 }
+
+// spans returns the ranges of s.
+func spans[V bitset.Value](s *rangeset.Bitset[V]) (ss []span) {
+	it := s.Runs()
+	for r, ok := it.Next(); ok; r, ok = it.Next() {
+		if r.Set {
+			ss = append(ss, span{uint64(r.Start), uint64(r.End)})
+		}
+	}
+	return
+}
+
+// sweep walks the ascending spans of a and b together, and emits the segments whose membership keep accepts.
+func sweep(a, b []span, keep func(inA, inB bool) bool, emit func(lo, hi uint64)) {
+	pos, i, j := uint64(0), 0, 0
+	for i < len(a) || j < len(b) {
+		inA := i < len(a) && a[i].lo <= pos
+		inB := j < len(b) && b[j].lo <= pos
+
+		// the segment ends where either operand next changes
+		end := uint64(math.MaxUint64)
+		if i < len(a) {
+			end = segmentEnd(end, a[i], inA)
+		}
+		if j < len(b) {
+			end = segmentEnd(end, b[j], inB)
+		}
+
+		if keep(inA, inB) {
+			emit(pos, end)
+		}
+		if inA && end == a[i].hi {
+			i++
+		}
+		if inB && end == b[j].hi {
+			j++
+		}
+		if end == math.MaxUint64 {
+			return
+		}
+		pos = end + 1
+	}
+}
+
+// segmentEnd returns the lesser of end and where an operand's membership next changes: the end of s when inside it,
+// otherwise just before its start.
+func segmentEnd(end uint64, s span, inside bool) uint64 {
+	hi := s.hi
+	if !inside {
+		hi = s.lo - 1
+	}
+	if hi < end {
+		return hi
+	}
+	return end
+}
+
+// writer adds members to dst, a range at a time when dst supports it, and otherwise in batches.
+type writer[V bitset.Value] struct {
+	dst   bitset.Bitset[V]
+	r     ranger[V]
+	batch []V
+}
+
+func newWriter[V bitset.Value](dst bitset.Bitset[V]) *writer[V] {
+	r, _ := dst.(ranger[V])
+	return &writer[V]{dst: dst, r: r}
+}
+
+func (w *writer[V]) add(v V) {
+	if w.batch == nil {
+		w.batch = make([]V, 0, 1024)
+	}
+	w.batch = append(w.batch, v)
+	if len(w.batch) == cap(w.batch) {
+		w.flush()
+	}
+}
+
+func (w *writer[V]) span(lo, hi V) {
+	if w.r != nil {
+		w.r.SetRange(lo, hi)
+		return
+	}
+	for v := lo; ; v++ {
+		w.add(v)
+		if v == hi {
+			break
+		}
+	}
+}
+
+// within adds the members of src in [lo, hi].
+func (w *writer[V]) within(src dense[V], lo, hi V) {
+	if m, ok := w.dst.(masker[V]); ok && m.OrMasked(src, lo, hi) {
+		return
+	}
+	for v, ok := src.NextSet(lo); ok && v <= hi; v, ok = src.NextSet(v + 1) {
+		w.add(v)
+		if v == hi {
+			break
+		}
+	}
+}
+
+func (w *writer[V]) flush() {
+	if len(w.batch) > 0 {
+		w.dst.Set(w.batch...)
+		w.batch = w.batch[:0]
+	}
+}
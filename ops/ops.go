@@ -0,0 +1,123 @@
+// Package ops computes binary operations between sets of different backends, without converting either operand.
+//
+// Each operation is dispatched to the most specific kernel available for the pair of operands:
+// first one installed with Register for their exact types, then a built-in kernel for the backends' capabilities,
+// and finally the streaming merges of the iterable package, which work for anything.
+package ops
+
+import (
+	"reflect"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Op is a binary set operation.
+type Op int
+
+const (
+	OpAnd    Op = iota // members of both a and b
+	OpOr               // members of either a or b
+	OpAndNot           // members of a which aren't members of b
+	OpXor              // members of exactly one of a and b
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpAnd:
+		return "and"
+	case OpOr:
+		return "or"
+	case OpAndNot:
+		return "and-not"
+	case OpXor:
+		return "xor"
+	}
+	return "unknown"
+}
+
+// commutative reports whether the operands can be swapped, to find a kernel.
+func (op Op) commutative() bool {
+	return op != OpAndNot
+}
+
+// keep reports whether a value belongs in the result, given its membership of each operand.
+func (op Op) keep(inA, inB bool) bool {
+	switch op {
+	case OpAnd:
+		return inA && inB
+	case OpOr:
+		return inA || inB
+	case OpAndNot:
+		return inA && !inB
+	}
+	return inA != inB
+}
+
+// Kernel computes an operation on a and b, into dst. dst is empty when the kernel is called.
+type Kernel[V bitset.Value] func(dst bitset.Bitset[V], a, b iterable.Iterable[V])
+
+// And replaces the members of dst with the intersection of a and b.
+// dst must not be either operand.
+func And[V bitset.Value](dst bitset.Bitset[V], a, b iterable.Iterable[V]) {
+	Apply(OpAnd, dst, a, b)
+}
+
+// Or replaces the members of dst with the union of a and b.
+// dst must not be either operand.
+func Or[V bitset.Value](dst bitset.Bitset[V], a, b iterable.Iterable[V]) {
+	Apply(OpOr, dst, a, b)
+}
+
+// AndNot replaces the members of dst with the members of a which aren't in b.
+// dst must not be either operand.
+func AndNot[V bitset.Value](dst bitset.Bitset[V], a, b iterable.Iterable[V]) {
+	Apply(OpAndNot, dst, a, b)
+}
+
+// Xor replaces the members of dst with the members of exactly one of a and b.
+// dst must not be either operand.
+func Xor[V bitset.Value](dst bitset.Bitset[V], a, b iterable.Iterable[V]) {
+	Apply(OpXor, dst, a, b)
+}
+
+// Apply replaces the members of dst with the result of op on a and b.
+// dst must not be either operand.
+func Apply[V bitset.Value](op Op, dst bitset.Bitset[V], a, b iterable.Iterable[V]) {
+	if same(dst, a) || same(dst, b) {
+		panic("ops: dst must not be an operand")
+	}
+
+	k := lookup[V](op, a, b)
+	dst.Clear()
+	k(dst, a, b)
+}
+
+// lookup finds the most specific kernel for op on a and b.
+func lookup[V bitset.Value](op Op, a, b iterable.Iterable[V]) Kernel[V] {
+	if k := registered[V](op, reflect.TypeOf(a), reflect.TypeOf(b)); k != nil {
+		return k
+	}
+	if op.commutative() {
+		if k := registered[V](op, reflect.TypeOf(b), reflect.TypeOf(a)); k != nil {
+			return func(dst bitset.Bitset[V], a, b iterable.Iterable[V]) { k(dst, b, a) }
+		}
+	}
+
+	if k := builtin[V](op, a, b); k != nil {
+		return k
+	}
+	if op.commutative() {
+		if k := builtin[V](op, b, a); k != nil {
+			return func(dst bitset.Bitset[V], a, b iterable.Iterable[V]) { k(dst, b, a) }
+		}
+	}
+
+	return stream[V](op)
+}
+
+// same reports whether two sets are the same pointer.
+func same(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Pointer && vb.Kind() == reflect.Pointer && va.Pointer() == vb.Pointer()
+}
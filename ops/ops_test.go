@@ -0,0 +1,121 @@
+package ops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/bools"
+	"github.com/zblach/go-bitset/dense/hier"
+	"github.com/zblach/go-bitset/internal/backends"
+	"github.com/zblach/go-bitset/iterable"
+	mapset "github.com/zblach/go-bitset/sparse/map"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+// expected computes op on a and b member by member.
+func expected(op Op, a, b []uint16) (vals []uint16) {
+	in := func(s []uint16, v uint16) bool {
+		for _, m := range s {
+			if m == v {
+				return true
+			}
+		}
+		return false
+	}
+	for v := 0; v <= 0xffff; v++ {
+		if op.keep(in(a, uint16(v)), in(b, uint16(v))) {
+			vals = append(vals, uint16(v))
+		}
+	}
+	return
+}
+
+func TestApply(t *testing.T) {
+	var a, b []uint16
+	for v := uint16(0); v < 300; v++ {
+		if v%3 != 0 {
+			a = append(a, v)
+		}
+	}
+	a = append(a, 1000, 65535)
+	for v := uint16(50); v < 120; v++ {
+		b = append(b, v)
+	}
+	b = append(b, 0, 1, 299, 5000, 65534, 65535)
+
+	for _, op := range []Op{OpAnd, OpOr, OpAndNot, OpXor} {
+		want := expected(op, a, b)
+
+		for aName, mkA := range backends.All[uint16]() {
+			for bName, mkB := range backends.All[uint16]() {
+				for dstName, mkDst := range backends.All[uint16]() {
+					sa, sb, dst := mkA(), mkB(), mkDst()
+					sa.Set(a...)
+					sb.Set(b...)
+					dst.Set(7, 40000) // replaced
+
+					Apply[uint16](op, dst, sa, sb)
+					assert.Equal(t, want, iterable.Values[uint16](dst), "%s %s %s -> %s", aName, op, bName, dstName)
+				}
+			}
+		}
+	}
+}
+
+func TestApply_Empty(t *testing.T) {
+	for aName, mkA := range backends.All[uint16]() {
+		for bName, mkB := range backends.All[uint16]() {
+			sa, sb := mkA(), mkB()
+			sa.Set(3, 4, 5)
+
+			dst := bits.New[uint64, uint16](0)
+			AndNot[uint16](dst, sa, sb)
+			assert.Equal(t, []uint16{3, 4, 5}, iterable.Values[uint16](dst), "%s - %s", aName, bName)
+			And[uint16](dst, sa, sb)
+			assert.Empty(t, iterable.Values[uint16](dst), "%s & %s", aName, bName)
+		}
+	}
+}
+
+func TestApply_Alias(t *testing.T) {
+	a, b := rangeset.New[uint](), rangeset.New[uint]()
+	assert.Panics(t, func() { Or[uint](a, a, b) })
+	assert.Panics(t, func() { Or[uint](b, a, b) })
+}
+
+func TestRegister(t *testing.T) {
+	calls := 0
+	Register(OpAnd, func(dst bitset.Bitset[uint32], a *hier.Bitset[uint32], b *bools.Bitset[uint32]) {
+		calls++
+		dst.Set(42)
+	})
+
+	h, bs := hier.New[uint32](0), bools.New[uint32](0)
+	dst := mapset.New[uint32]()
+
+	And[uint32](dst, h, bs)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []uint32{42}, iterable.Values[uint32](dst))
+
+	And[uint32](dst, bs, h) // commutative, so swapped
+	assert.Equal(t, 2, calls)
+
+	AndNot[uint32](dst, h, bs) // a different op
+	assert.Equal(t, 2, calls)
+
+	And[uint16](mapset.New[uint16](), hier.New[uint16](0), bools.New[uint16](0)) // a different V
+	assert.Equal(t, 2, calls)
+}
+
+func TestSweep(t *testing.T) {
+	var got []span
+	sweep(
+		[]span{{0, 9}, {20, 29}, {100, 1<<64 - 1}},
+		[]span{{5, 24}, {40, 40}},
+		OpXor.keep,
+		func(lo, hi uint64) { got = append(got, span{lo, hi}) },
+	)
+	assert.Equal(t, []span{{0, 4}, {10, 19}, {25, 29}, {40, 40}, {100, 1<<64 - 1}}, got)
+}
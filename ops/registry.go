@@ -0,0 +1,48 @@
+package ops
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+type key struct {
+	op   Op
+	a, b reflect.Type
+}
+
+var (
+	lock     sync.RWMutex
+	registry = map[key]any{} // Kernel[V], for the V of the operands
+)
+
+// Register installs a kernel for op, for operands of exactly types A and B. It takes precedence over the built-in
+// kernels, and replaces any kernel previously registered for the same types. For commutative operations, it's also
+// used with the operands swapped.
+//
+// Backends outside of this module use Register to provide their own fast paths, once per instantiation:
+//
+//	ops.Register(ops.OpAnd, func(dst bitset.Bitset[uint], a *mine.Set[uint], b *rangeset.Bitset[uint]) { ... })
+func Register[V bitset.Value, A, B iterable.Iterable[V]](op Op, k func(dst bitset.Bitset[V], a A, b B)) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	registry[key{op, typeOf[A](), typeOf[B]()}] = Kernel[V](func(dst bitset.Bitset[V], a, b iterable.Iterable[V]) {
+		k(dst, a.(A), b.(B))
+	})
+}
+
+// registered returns the kernel registered for op on operands of types a and b, if there is one.
+func registered[V bitset.Value](op Op, a, b reflect.Type) Kernel[V] {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	k, _ := registry[key{op, a, b}].(Kernel[V])
+	return k
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
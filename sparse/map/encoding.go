@@ -0,0 +1,58 @@
+package mapset
+
+import (
+	"database/sql/driver"
+
+	"github.com/zblach/go-bitset/codec"
+)
+
+// MarshalJSON implements json.Marshaler, as an array of members. See codec.JSON for the other encodings.
+func (s *Bitset[V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON[V](s, codec.Members)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting any of codec's JSON encodings.
+func (s *Bitset[V]) UnmarshalJSON(data []byte) error {
+	s.initialize()
+	return codec.UnmarshalJSON[V](s, data)
+}
+
+// MarshalText implements encoding.TextMarshaler, as a list of members and ranges: 1,3,5-9
+func (s *Bitset[V]) MarshalText() ([]byte, error) {
+	return codec.MarshalText[V](s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Bitset[V]) UnmarshalText(text []byte) error {
+	s.initialize()
+	return codec.UnmarshalText[V](s, text)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, in codec's binary format.
+func (s *Bitset[V]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary[V](s), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *Bitset[V]) UnmarshalBinary(data []byte) error {
+	s.initialize()
+	return codec.UnmarshalBinary[V](s, data)
+}
+
+// Value implements driver.Valuer, for bytea columns. See codec.SQL for bit varying.
+func (s *Bitset[V]) Value() (driver.Value, error) {
+	return codec.Value[V](s, codec.Bytea)
+}
+
+// Scan implements sql.Scanner, accepting either of codec's SQL encodings.
+func (s *Bitset[V]) Scan(src any) error {
+	s.initialize()
+	return codec.Scan[V](s, src)
+}
+
+// initialize prepares a zero bitset, such as one allocated by a decoder, for use.
+func (s *Bitset[V]) initialize() {
+	if s.lock == nil {
+		*s = *New[V]()
+	}
+}
@@ -30,6 +30,27 @@ func (s *Bitset[V]) Iterate() (iterable.Iter[V], uint) {
 	return it, s.pop
 }
 
+// IterateReverse implements iterable.Reversible
+func (s *Bitset[V]) IterateReverse() (iterable.Iter[V], uint) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	it := &Iterator[V]{
+		lock: &sync.RWMutex{},
+		keys: make([]V, 0, s.pop),
+	}
+
+	for k := range s.values {
+		it.keys = append(it.keys, k)
+	}
+
+	sort.Slice(it.keys, func(i, j int) bool {
+		return it.keys[i] > it.keys[j]
+	})
+
+	return it, s.pop
+}
+
 type Iterator[V bitset.Value] struct {
 	lock  *sync.RWMutex
 	keys  []V
@@ -49,6 +70,7 @@ func (it *Iterator[V]) Next() (V, bool) {
 }
 
 var (
-	_ iterable.Iter[uint]     = (*Iterator[uint])(nil)
-	_ iterable.Iterable[rune] = (*Bitset[rune])(nil)
+	_ iterable.Iter[uint]       = (*Iterator[uint])(nil)
+	_ iterable.Iterable[rune]   = (*Bitset[rune])(nil)
+	_ iterable.Reversible[rune] = (*Bitset[rune])(nil)
 )
@@ -4,6 +4,8 @@ import (
 	"sync"
 
 	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/cow"
+	"github.com/zblach/go-bitset/internal/wait"
 	"github.com/zblach/go-bitset/mixin/logical"
 )
 
@@ -16,8 +18,12 @@ type Bitset[V bitset.Value] struct {
 
 	lock *sync.RWMutex
 
-	values map[V]noneT
-	pop    uint
+	values  map[V]noneT
+	pop     uint
+	peak    int                  // most entries held since the last Clear, as maps never shrink
+	version uint64               // incremented on every modification
+	log     cow.KeyLog[V, noneT] // original membership, for snapshots
+	waiters wait.Signal          // woken as members are added
 }
 
 func New[V bitset.Value]() *Bitset[V] {
@@ -31,16 +37,20 @@ func (s *Bitset[V]) Clear() {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	s.log.Freeze(s.values)
 	s.values = map[V]noneT{}
 	s.pop = 0
+	s.peak = 0
+	s.version++
 }
 
 func (s *Bitset[V]) Copy() *Bitset[V] {
-	s.lock.Lock()
+	s.lock.RLock()
 	defer s.lock.RUnlock()
 
 	clone := New[V]()
 	clone.pop = s.pop
+	clone.peak = len(s.values)
 	for k, v := range s.values {
 		clone.values[k] = v
 	}
@@ -53,6 +63,10 @@ func (s *Bitset[V]) Get(index V) bool {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
+	return s.get(index)
+}
+
+func (s *Bitset[V]) get(index V) bool {
 	_, ok := s.values[index]
 	return ok
 }
@@ -66,12 +80,19 @@ func (s *Bitset[V]) Set(indices ...V) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	pop := s.pop
 	for _, index := range indices {
 		if _, ok := s.values[index]; !ok {
+			s.log.Touch(index, false)
 			s.values[index] = none
 			s.pop += 1
 		}
 	}
+	if s.pop != pop {
+		s.grew()
+		s.version++
+		s.waiters.Notify()
+	}
 }
 
 // Unset implements bitset.Bitset
@@ -83,12 +104,17 @@ func (s *Bitset[V]) Unset(indices ...V) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	pop := s.pop
 	for _, index := range indices {
 		if _, ok := s.values[index]; ok {
+			s.log.Touch(index, true)
 			delete(s.values, index)
 			s.pop -= 1
 		}
 	}
+	if s.pop != pop {
+		s.version++
+	}
 }
 
 // And implements bitset.Logical
@@ -143,12 +169,15 @@ func (a *Bitset[V]) Or(b *Bitset[V]) (aOrB *Bitset[V]) {
 	return
 }
 
-// Cap implements bitset.Inspect
+// Cap implements bitset.Inspect. Maps never shrink, so it's the most entries held since the last Clear.
 func (s *Bitset[V]) Cap() int {
-	return s.Len()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.peak
 }
 
-// Len implements bitset.Inspect
+// Len implements bitset.Inspect. It's the number of entries, which is also the number of members.
 func (s *Bitset[V]) Len() int {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -156,6 +185,13 @@ func (s *Bitset[V]) Len() int {
 	return len(s.values)
 }
 
+// grew records the peak number of entries, after values are set.
+func (s *Bitset[V]) grew() {
+	if len(s.values) > s.peak {
+		s.peak = len(s.values)
+	}
+}
+
 // Pop implements bitset.Inspect
 func (s *Bitset[V]) Pop() uint {
 	s.lock.RLock()
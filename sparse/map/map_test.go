@@ -0,0 +1,92 @@
+package mapset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+func TestPage(t *testing.T) {
+	s := New[uint]()
+	s.Set(202, 0, 65, 1, 200, 64, 201)
+
+	vals, next, done := s.Page(bitset.Cursor[uint]{}, 3)
+	assert.Equal(t, []uint{0, 1, 64}, vals)
+	assert.False(t, done)
+
+	s.Unset(1)
+	vals, next, done = s.Page(next, 4)
+	assert.Equal(t, []uint{65, 200, 201, 202}, vals)
+	assert.True(t, done)
+	assert.True(t, next.Changed())
+}
+
+func TestIterateReverse(t *testing.T) {
+	s := New[uint]()
+	s.Set(10, 0, 4, 3)
+
+	assert.Equal(t, []uint{10, 4, 3, 0}, iterable.Values(iterable.Reverse[uint](s)))
+}
+
+func TestSetRange(t *testing.T) {
+	s := New[uint8]()
+	s.SetRange(250, 255)
+	s.SetRange(0, 2)
+	s.UnsetRange(1, 251)
+	assert.Equal(t, []uint8{0, 252, 253, 254, 255}, iterable.Values[uint8](s))
+	assert.Equal(t, uint(5), s.Pop())
+}
+
+func TestInspect(t *testing.T) {
+	s := New[uint]()
+	s.SetRange(0, 99)
+	before := s.Bytes()
+
+	s.UnsetRange(10, 99)
+	assert.Equal(t, 10, s.Len())
+	assert.Equal(t, 100, s.Cap(), "maps never shrink")
+	assert.Equal(t, before, s.Bytes())
+
+	c := s.Copy()
+	assert.Equal(t, 10, c.Cap())
+	assert.Less(t, c.Bytes(), before)
+
+	s.Clear()
+	assert.Equal(t, 0, s.Cap())
+}
+
+func TestSnapshot(t *testing.T) {
+	s := New[uint]()
+	s.Set(1, 2, 3)
+
+	snap := s.Snapshot()
+	s.Unset(2)
+	s.SetRange(10, 20)
+	later := s.Snapshot()
+	s.Set(2)
+	s.Clear()
+	s.Set(99)
+
+	assert.True(t, snap.Get(2))
+	assert.False(t, snap.Get(10))
+	assert.Equal(t, []uint{1, 2, 3}, iterable.Values[uint](snap))
+	assert.False(t, later.Get(2))
+	assert.Equal(t, uint(13), later.Pop())
+	assert.Equal(t, "{1, 3, 10-20}", later.String())
+	assert.Equal(t, []uint{99}, iterable.Values[uint](s))
+}
+
+func TestCopy(t *testing.T) {
+	s := New[uint]()
+	s.Set(1, 2, 300)
+
+	// Copy once took the write lock and released a read lock, which is fatal
+	c := s.Copy()
+	c.Set(4)
+	s.Set(5)
+
+	assert.Equal(t, []uint{1, 2, 4, 300}, iterable.Values[uint](c))
+	assert.Equal(t, []uint{1, 2, 5, 300}, iterable.Values[uint](s))
+}
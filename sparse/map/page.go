@@ -0,0 +1,44 @@
+package mapset
+
+import (
+	"sort"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Version is incremented every time the bitset is modified.
+func (s *Bitset[V]) Version() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.version
+}
+
+// Page implements bitset.Pager
+// Map keys are unordered, so every page examines every key.
+func (s *Bitset[V]) Page(after bitset.Cursor[V], limit int) (vals []V, next bitset.Cursor[V], done bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	from, ok := after.Start()
+	if !ok {
+		return nil, after.Advance(nil, s.version), true
+	}
+
+	for k := range s.values {
+		if k >= from {
+			vals = append(vals, k)
+		}
+	}
+	sort.Slice(vals, func(i, j int) bool {
+		return vals[i] < vals[j]
+	})
+
+	done = true
+	if limit > 0 && len(vals) > limit {
+		vals, done = vals[:limit], false
+	}
+	return vals, after.Advance(vals, s.version), done
+}
+
+var _ bitset.Pager[uint] = (*Bitset[uint])(nil)
@@ -0,0 +1,53 @@
+package mapset
+
+// SetRange sets every value in [lo, hi].
+func (s *Bitset[V]) SetRange(lo, hi V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.setRange(lo, hi)
+}
+
+// UnsetRange unsets every value in [lo, hi].
+func (s *Bitset[V]) UnsetRange(lo, hi V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.unsetRange(lo, hi)
+}
+
+func (s *Bitset[V]) setRange(lo, hi V) {
+	pop := s.pop
+	for v := lo; v <= hi; v++ {
+		if _, ok := s.values[v]; !ok {
+			s.log.Touch(v, false)
+			s.values[v] = none
+			s.pop++
+		}
+		if v == hi {
+			break
+		}
+	}
+	if s.pop != pop {
+		s.grew()
+		s.version++
+		s.waiters.Notify()
+	}
+}
+
+func (s *Bitset[V]) unsetRange(lo, hi V) {
+	pop := s.pop
+	for v := lo; v <= hi; v++ {
+		if _, ok := s.values[v]; ok {
+			s.log.Touch(v, true)
+			delete(s.values, v)
+			s.pop--
+		}
+		if v == hi {
+			break
+		}
+	}
+	if s.pop != pop {
+		s.version++
+	}
+}
@@ -0,0 +1,93 @@
+package mapset
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/cow"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Snapshot returns a read-only view of the bitset as it is now, in constant time. Unlike Copy, no entries are
+// duplicated up front; each value's membership is saved for the snapshot when it's first modified afterwards.
+func (s *Bitset[V]) Snapshot() *Snapshot[V] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snap := &Snapshot[V]{
+		src:     s,
+		gen:     s.log.Snapshot(),
+		pop:     s.pop,
+		version: s.version,
+	}
+	// dropped snapshots are released when collected
+	runtime.SetFinalizer(snap, (*Snapshot[V]).Release)
+	return snap
+}
+
+// Snapshot is the members of a bitset at one point. It's unaffected by later modifications to the bitset, and is
+// safe for concurrent use.
+type Snapshot[V bitset.Value] struct {
+	src     *Bitset[V]
+	gen     *cow.KeyGen[V, noneT]
+	pop     uint
+	version uint64
+}
+
+// Get returns whether index was a member.
+func (s *Snapshot[V]) Get(index V) bool {
+	s.src.lock.RLock()
+	defer s.src.lock.RUnlock()
+
+	return s.gen.Member(s.src.values, index)
+}
+
+// Pop returns the number of members.
+func (s *Snapshot[V]) Pop() uint {
+	return s.pop
+}
+
+// Version returns the bitset's version when the snapshot was taken.
+func (s *Snapshot[V]) Version() uint64 {
+	return s.version
+}
+
+// Release stops the bitset saving its contents for the snapshot, which mustn't be used afterwards. A snapshot which
+// is dropped is released once it's garbage collected; Release just does so promptly.
+func (s *Snapshot[V]) Release() {
+	s.src.lock.Lock()
+	defer s.src.lock.Unlock()
+
+	if s.gen != nil {
+		s.src.log.Release(s.gen)
+		s.gen = nil
+	}
+}
+
+// Iterate implements iterable.Iterable. As with the bitset, the members are collected and sorted up front.
+func (s *Snapshot[V]) Iterate() (iterable.Iter[V], uint) {
+	it := &Iterator[V]{
+		lock: &sync.RWMutex{},
+		keys: make([]V, 0, s.pop),
+	}
+
+	s.src.lock.RLock()
+	s.gen.Keys(s.src.values, func(k V) { it.keys = append(it.keys, k) })
+	s.src.lock.RUnlock()
+
+	sort.Slice(it.keys, func(i, j int) bool {
+		return it.keys[i] < it.keys[j]
+	})
+
+	return it, s.pop
+}
+
+// String implements fmt.Stringer, e.g. {1, 3, 5-9}.
+func (s *Snapshot[V]) String() string {
+	it, _ := s.Iterate()
+	return bitset.String[V](it)
+}
+
+var _ iterable.Iterable[uint] = (*Snapshot[uint])(nil)
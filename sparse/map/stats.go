@@ -0,0 +1,32 @@
+package mapset
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Bytes implements bitset.Inspect: the bitset, its lock, and an estimate of the map's size.
+//
+// Maps never shrink, so the estimate is for the most entries held since the last Clear: a header, and enough buckets
+// of 8 keys to keep them at most 6.5/8ths full.
+func (s *Bitset[V]) Bytes() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	const header, perBucket = 48, 8 + 8 // hmap, and each bucket's hashes and overflow pointer
+
+	buckets := 1
+	for float64(s.peak) > 6.5*float64(buckets) {
+		buckets *= 2
+	}
+
+	var key V
+	return int(unsafe.Sizeof(*s)+unsafe.Sizeof(*s.lock)) + header + buckets*(perBucket+8*int(unsafe.Sizeof(key)))
+}
+
+// Stats implements bitset.Inspect
+func (s *Bitset[V]) Stats() bitset.Stats[V] {
+	return iterable.Measure(s.Bytes(), s.Runs())
+}
@@ -0,0 +1,69 @@
+package persistent
+
+import (
+	"database/sql/driver"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/codec"
+)
+
+// MarshalJSON implements json.Marshaler, as an array of members. See codec.JSON for the other encodings.
+func (s Bitset[V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON[V](s, codec.Members)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting any of codec's JSON encodings.
+func (s *Bitset[V]) UnmarshalJSON(data []byte) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.UnmarshalJSON(dst, data) })
+}
+
+// MarshalText implements encoding.TextMarshaler, as a list of members and ranges: 1,3,5-9
+func (s Bitset[V]) MarshalText() ([]byte, error) {
+	return codec.MarshalText[V](s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Bitset[V]) UnmarshalText(text []byte) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.UnmarshalText(dst, text) })
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, in codec's binary format.
+func (s Bitset[V]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary[V](s), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *Bitset[V]) UnmarshalBinary(data []byte) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.UnmarshalBinary(dst, data) })
+}
+
+// Value implements driver.Valuer, for bytea columns. See codec.SQL for bit varying.
+func (s Bitset[V]) Value() (driver.Value, error) {
+	return codec.Value[V](s, codec.Bytea)
+}
+
+// Scan implements sql.Scanner, accepting either of codec's SQL encodings.
+func (s *Bitset[V]) Scan(src any) error {
+	return s.decode(func(dst bitset.Bitset[V]) error { return codec.Scan(dst, src) })
+}
+
+// decode replaces s with a version built by fn. Decoding only replaces what s refers to; earlier versions, which
+// may be shared, are unaffected.
+func (s *Bitset[V]) decode(fn func(dst bitset.Bitset[V]) error) error {
+	b := &builder[V]{s: *s}
+	if err := fn(b); err != nil {
+		return err
+	}
+	*s = b.s
+	return nil
+}
+
+// builder adapts a version to bitset.Bitset, for the decoders, by deriving a new version for every change.
+type builder[V bitset.Value] struct {
+	s Bitset[V]
+}
+
+func (b *builder[V]) Get(index V) bool   { return b.s.Get(index) }
+func (b *builder[V]) Set(indices ...V)   { b.s = b.s.With(indices...) }
+func (b *builder[V]) Unset(indices ...V) { b.s = b.s.Without(indices...) }
+func (b *builder[V]) Clear()             { b.s = Bitset[V]{} }
@@ -0,0 +1,67 @@
+package persistent
+
+import (
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Iterate implements iterable.Iterable. Versions are immutable, so the iterator walks the set itself.
+func (s Bitset[V]) Iterate() (iterable.Iter[V], uint) {
+	return &Iterator[V]{set: s}, s.Pop()
+}
+
+// Iterator skips from member to member, down the tree.
+type Iterator[V bitset.Value] struct {
+	set Bitset[V]
+
+	pos  V
+	done bool
+}
+
+func (it *Iterator[V]) Next() (V, bool) {
+	if it.done {
+		return 0, false
+	}
+
+	idx, ok := it.set.NextSet(it.pos)
+	if !ok || idx+1 < idx {
+		// the last member, or the end of the range of V
+		it.done = true
+	}
+	it.pos = idx + 1
+	return idx, ok
+}
+
+// IterateReverse implements iterable.Reversible
+func (s Bitset[V]) IterateReverse() (iterable.Iter[V], uint) {
+	return &ReverseIterator[V]{set: s, end: ^V(0), done: s.root == nil}, s.Pop()
+}
+
+// ReverseIterator enumerates a set in descending order.
+type ReverseIterator[V bitset.Value] struct {
+	set Bitset[V]
+
+	end  V // inclusive upper bound of the next value
+	done bool
+}
+
+func (it *ReverseIterator[V]) Next() (V, bool) {
+	if it.done {
+		return 0, false
+	}
+
+	idx, ok := it.set.PrevSet(it.end)
+	if !ok || idx == 0 {
+		it.done = true
+	} else {
+		it.end = idx - 1
+	}
+	return idx, ok
+}
+
+var (
+	_ iterable.Iter[uint]       = (*Iterator[uint])(nil)
+	_ iterable.Iter[uint]       = (*ReverseIterator[uint])(nil)
+	_ iterable.Iterable[rune]   = Bitset[rune]{}
+	_ iterable.Reversible[rune] = Bitset[rune]{}
+)
@@ -0,0 +1,95 @@
+package persistent
+
+import (
+	mb "math/bits"
+)
+
+// NextSet returns the first member at or after from. Empty subtrees are skipped over.
+func (s Bitset[V]) NextSet(from V) (V, bool) {
+	if !s.covers(uint(from)) {
+		return 0, false
+	}
+	idx, ok := next(s.root, s.height, 0, uint(from))
+	return V(idx), ok
+}
+
+// PrevSet returns the last member at or before from. Empty subtrees are skipped over.
+func (s Bitset[V]) PrevSet(from V) (V, bool) {
+	if s.root == nil {
+		return 0, false
+	}
+	if pos := uint(from); !s.covers(pos) {
+		from = V(span(s.height) - 1) // the last value under the root. at full height, this wraps around to the largest uint.
+	}
+	idx, ok := prev(s.root, s.height, 0, uint(from))
+	return V(idx), ok
+}
+
+// span is the number of values under a node of height h. It's 0 when that's every uint.
+func span(h uint) uint {
+	return 1 << (leafBits + kidBits*h)
+}
+
+// next finds the first member at or after from, under n, of height h, which starts at base.
+func next(n *node, h uint, base, from uint) (uint, bool) {
+	if h == 0 {
+		for i := (from - base) / 64; i < leafWords; i++ {
+			w := n.words[i]
+			if start := base + i*64; from > start {
+				w &= ^uint64(0) << (from - start)
+			}
+			if w != 0 {
+				return base + i*64 + uint(mb.TrailingZeros64(w)), true
+			}
+		}
+		return 0, false
+	}
+
+	width := span(h - 1)
+	for i := (from - base) / width; i < fanout; i++ {
+		k := n.kids[i]
+		if k == nil {
+			continue
+		}
+		start := base + i*width
+		if from < start {
+			from = start
+		}
+		if idx, ok := next(k, h-1, start, from); ok {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// prev finds the last member at or before from, under n, of height h, which starts at base.
+func prev(n *node, h uint, base, from uint) (uint, bool) {
+	if h == 0 {
+		for i := int((from - base) / 64); i >= 0; i-- {
+			w := n.words[i]
+			if end := base + uint(i)*64 + 63; from < end {
+				w &= ^uint64(0) >> (end - from)
+			}
+			if w != 0 {
+				return base + uint(i)*64 + 63 - uint(mb.LeadingZeros64(w)), true
+			}
+		}
+		return 0, false
+	}
+
+	width := span(h - 1)
+	for i := int((from - base) / width); i >= 0; i-- {
+		k := n.kids[i]
+		if k == nil {
+			continue
+		}
+		start := base + uint(i)*width
+		if end := start + width - 1; from > end {
+			from = end
+		}
+		if idx, ok := prev(k, h-1, start, from); ok {
+			return idx, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,345 @@
+package persistent
+
+import (
+	mb "math/bits"
+
+	"github.com/zblach/go-bitset"
+)
+
+const (
+	leafBits  = 9 // each leaf holds 512 values
+	leafWords = 1 << leafBits / 64
+	kidBits   = 5 // each inner node has 32 children
+	fanout    = 1 << kidBits
+)
+
+// Bitset is an immutable set, stored as a radix tree of fixed-size chunks of words. The zero value is an empty set.
+//
+// With, Without, And and Or return new versions, which share every chunk they didn't change with the original.
+// Nothing is ever modified after it's built, so versions need no locks, and can be shared freely between goroutines.
+// Empty subtrees aren't stored, so the set is sparse at the granularity of its 512-value leaves.
+type Bitset[V bitset.Value] struct {
+	tree
+}
+
+// node is a leaf, holding words, or an inner node, holding kids. Missing kids are empty.
+type node struct {
+	pop  uint
+	size uint // values held by the leaves under this node
+
+	kids  []*node
+	words []uint64
+}
+
+// New returns a set of the given values.
+func New[V bitset.Value](vals ...V) Bitset[V] {
+	return Bitset[V]{}.With(vals...)
+}
+
+// Get returns whether or not a value is in the set.
+func (s Bitset[V]) Get(index V) bool {
+	pos := uint(index)
+	if !s.covers(pos) {
+		return false
+	}
+
+	n := s.root
+	for h := s.height; n != nil && h > 0; h-- {
+		n = n.kids[pos>>(leafBits+kidBits*(h-1))%fanout]
+	}
+	return n != nil && n.words[pos%(1<<leafBits)/64]&(1<<(pos%64)) != 0
+}
+
+// covers reports whether pos is within the range of the tree's current height.
+func (t tree) covers(pos uint) bool {
+	return t.root != nil && pos>>leafBits>>(kidBits*t.height) == 0
+}
+
+// With returns a version of the set with the values added.
+func (s Bitset[V]) With(vals ...V) Bitset[V] {
+	e := editor{}
+	for _, v := range vals {
+		if !s.Get(v) {
+			s = Bitset[V]{e.set(s.tree, uint(v), true)}
+		}
+	}
+	return s
+}
+
+// Without returns a version of the set with the values removed.
+func (s Bitset[V]) Without(vals ...V) Bitset[V] {
+	e := editor{}
+	for _, v := range vals {
+		if s.Get(v) {
+			s = Bitset[V]{e.set(s.tree, uint(v), false)}
+		}
+	}
+	return s
+}
+
+// And returns the values in both sets. Subtrees which the sets share, or which are unchanged, are reused.
+func (a Bitset[V]) And(b Bitset[V]) Bitset[V] {
+	h := a.height
+	if b.height > h {
+		h = b.height
+	}
+	return Bitset[V]{tree{and(lift(a.root, a.height, h), lift(b.root, b.height, h), h), h}.collapse()}
+}
+
+// Or returns the values in either set. Subtrees which the sets share, or which only one of them has, are reused.
+func (a Bitset[V]) Or(b Bitset[V]) Bitset[V] {
+	h := a.height
+	if b.height > h {
+		h = b.height
+	}
+	return Bitset[V]{tree{or(lift(a.root, a.height, h), lift(b.root, b.height, h), h), h}.collapse()}
+}
+
+// Inspection functions
+
+// Len is the number of values held by the tree's leaves.
+func (s Bitset[V]) Len() int {
+	if s.root == nil {
+		return 0
+	}
+	return int(s.root.size)
+}
+
+// Cap is the number of values held by the tree's leaves. It's the same as Len, as versions never grow in place.
+func (s Bitset[V]) Cap() int {
+	return s.Len()
+}
+
+// Pop is the number of values in the set.
+func (s Bitset[V]) Pop() uint {
+	if s.root == nil {
+		return 0
+	}
+	return s.root.pop
+}
+
+// tree is the untyped root of a version.
+type tree struct {
+	root   *node
+	height uint // levels of inner nodes above the leaves
+}
+
+// editor builds a new version of a tree. Nodes it has already copied are modified in place, so that changing several
+// values under the same node only copies it once.
+type editor struct {
+	owned map[*node]bool
+}
+
+// own returns a copy of n which may be modified, or a new node if n is nil.
+func (e *editor) own(n *node, leaf bool) *node {
+	if n != nil && e.owned[n] {
+		return n
+	}
+
+	c := &node{}
+	switch {
+	case leaf:
+		c.words = make([]uint64, leafWords)
+	default:
+		c.kids = make([]*node, fanout)
+	}
+	if n != nil {
+		copy(c.words, n.words)
+		copy(c.kids, n.kids)
+		c.pop, c.size = n.pop, n.size
+	}
+
+	if e.owned == nil {
+		e.owned = map[*node]bool{}
+	}
+	e.owned[c] = true
+	return c
+}
+
+// set adds or removes pos, returning the new tree.
+func (e *editor) set(t tree, pos uint, on bool) tree {
+	return e.setLeaf(t, pos, pos, on)
+}
+
+// setLeaf adds or removes every value in [lo, hi], which must be within a single leaf, returning the new tree.
+func (e *editor) setLeaf(t tree, lo, hi uint, on bool) tree {
+	if on {
+		if t.root == nil {
+			t.height = 0
+			for lo>>leafBits>>(kidBits*t.height) != 0 {
+				t.height++
+			}
+		}
+		for lo>>leafBits>>(kidBits*t.height) != 0 {
+			r := e.own(nil, false)
+			r.kids[0] = t.root
+			r.recount()
+			t.root, t.height = r, t.height+1
+		}
+	}
+
+	t.root = e.edit(t.root, t.height, lo, hi, on)
+	return t.collapse()
+}
+
+// edit adds or removes [lo, hi], within a single leaf, under n, returning the new node, or nil if it's now empty.
+func (e *editor) edit(n *node, h uint, lo, hi uint, on bool) *node {
+	n = e.own(n, h == 0)
+	if h == 0 {
+		first, last := lo%(1<<leafBits), hi%(1<<leafBits)
+		for i := first / 64; i <= last/64; i++ {
+			mask := ^uint64(0)
+			if start := i * 64; first > start {
+				mask &= ^uint64(0) << (first - start)
+			}
+			if end := i*64 + 63; last < end {
+				mask &= ^uint64(0) >> (end - last)
+			}
+			if on {
+				n.words[i] |= mask
+			} else {
+				n.words[i] &^= mask
+			}
+		}
+	} else {
+		i := lo >> (leafBits + kidBits*(h-1)) % fanout
+		n.kids[i] = e.edit(n.kids[i], h-1, lo, hi, on)
+	}
+
+	if n.recount(); n.pop == 0 {
+		return nil
+	}
+	return n
+}
+
+// recount recomputes the population and size of n from its words or kids.
+func (n *node) recount() {
+	n.pop, n.size = 0, 0
+	if n.words != nil {
+		for _, w := range n.words {
+			n.pop += uint(mb.OnesCount64(w))
+		}
+		n.size = 1 << leafBits
+		return
+	}
+	for _, k := range n.kids {
+		if k != nil {
+			n.pop += k.pop
+			n.size += k.size
+		}
+	}
+}
+
+// collapse removes root levels which only have a first child, so that the height is the minimum for the members.
+func (t tree) collapse() tree {
+	if t.root == nil {
+		return tree{}
+	}
+	for t.height > 0 {
+		for _, k := range t.root.kids[1:] {
+			if k != nil {
+				return t
+			}
+		}
+		t.root, t.height = t.root.kids[0], t.height-1
+	}
+	return t
+}
+
+// lift wraps n, of height from, in new first-child nodes until it's of height to.
+func lift(n *node, from, to uint) *node {
+	for ; n != nil && from < to; from++ {
+		r := &node{kids: make([]*node, fanout)}
+		r.kids[0] = n
+		r.recount()
+		n = r
+	}
+	return n
+}
+
+// and intersects two nodes of height h. Either is returned, rather than a copy, when the result is the same.
+func and(x, y *node, h uint) *node {
+	switch {
+	case x == nil || y == nil:
+		return nil
+	case x == y:
+		return x
+	}
+
+	if h == 0 {
+		return combine(x, y, func(a, b uint64) uint64 { return a & b })
+	}
+
+	kids := make([]*node, fanout)
+	for i := range kids {
+		kids[i] = and(x.kids[i], y.kids[i], h-1)
+	}
+	return join(x, y, kids)
+}
+
+// or unites two nodes of height h. Either is returned, rather than a copy, when the result is the same.
+func or(x, y *node, h uint) *node {
+	switch {
+	case x == nil:
+		return y
+	case y == nil || x == y:
+		return x
+	}
+
+	if h == 0 {
+		return combine(x, y, func(a, b uint64) uint64 { return a | b })
+	}
+
+	kids := make([]*node, fanout)
+	for i := range kids {
+		kids[i] = or(x.kids[i], y.kids[i], h-1)
+	}
+	return join(x, y, kids)
+}
+
+// combine merges the words of two leaves, reusing either if the result is the same.
+func combine(x, y *node, op func(a, b uint64) uint64) *node {
+	words := make([]uint64, leafWords)
+	sameX, sameY := true, true
+	for i := range words {
+		words[i] = op(x.words[i], y.words[i])
+		sameX = sameX && words[i] == x.words[i]
+		sameY = sameY && words[i] == y.words[i]
+	}
+
+	switch {
+	case sameX:
+		return x
+	case sameY:
+		return y
+	}
+	n := &node{words: words}
+	if n.recount(); n.pop == 0 {
+		return nil
+	}
+	return n
+}
+
+// join builds an inner node from merged kids, reusing either x or y if their kids are the same.
+func join(x, y *node, kids []*node) *node {
+	sameX, sameY := true, true
+	for i, k := range kids {
+		sameX = sameX && k == x.kids[i]
+		sameY = sameY && k == y.kids[i]
+	}
+
+	switch {
+	case sameX:
+		return x
+	case sameY:
+		return y
+	}
+	n := &node{kids: kids}
+	if n.recount(); n.pop == 0 {
+		return nil
+	}
+	return n
+}
+
+// interface adherence validation
+var _ bitset.Size = Bitset[uint]{}
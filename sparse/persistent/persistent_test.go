@@ -0,0 +1,188 @@
+package persistent
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+func TestWithWithout(t *testing.T) {
+	var empty Bitset[uint]
+	assert.False(t, empty.Get(0))
+	assert.Equal(t, uint(0), empty.Pop())
+
+	a := New[uint](1, 3, 600, 1_000_000)
+	b := a.With(2, 3)
+	c := b.Without(1, 1_000_000, 5)
+
+	assert.Equal(t, []uint{1, 3, 600, 1_000_000}, iterable.Values[uint](a), "unchanged by derivations")
+	assert.Equal(t, []uint{1, 2, 3, 600, 1_000_000}, iterable.Values[uint](b))
+	assert.Equal(t, []uint{2, 3, 600}, iterable.Values[uint](c))
+	assert.Equal(t, uint(3), c.Pop())
+	assert.Equal(t, uint(1), c.height, "collapsed once the high values are gone")
+	assert.Equal(t, 2*512, c.Len())
+
+	assert.Equal(t, Bitset[uint]{}, c.Without(2, 3, 600))
+	assert.Equal(t, a, a.With(1, 3), "no-ops return the same version")
+}
+
+func TestSharing(t *testing.T) {
+	a := New[uint](0, 512*40, 512*41)
+	b := a.With(512*41 + 1)
+
+	assert.Same(t, a.root.kids[0], b.root.kids[0], "untouched leaves are shared")
+	assert.NotSame(t, a.root.kids[1], b.root.kids[1])
+	assert.Same(t, a.root.kids[1].kids[8], b.root.kids[1].kids[8])
+
+	assert.Equal(t, a, a.And(b))
+	assert.Equal(t, b, a.Or(b))
+	assert.Equal(t, a, a.And(a))
+}
+
+func TestLogical(t *testing.T) {
+	small := New[uint64](1, 2, 3)
+	large := New[uint64](3, 4, 1<<40, 1<<63)
+
+	assert.Equal(t, []uint64{3}, iterable.Values[uint64](small.And(large)))
+	assert.Equal(t, []uint64{3}, iterable.Values[uint64](large.And(small)))
+	assert.Equal(t, uint(0), small.And(large).height)
+
+	or := small.Or(large)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 1 << 40, 1 << 63}, iterable.Values[uint64](or))
+	assert.Equal(t, or, large.Or(small))
+	assert.Equal(t, uint(6), or.Pop())
+
+	assert.Equal(t, Bitset[uint64]{}, New[uint64](5).And(New[uint64](1<<40)))
+}
+
+func TestNavigate(t *testing.T) {
+	s := New[uint64](0, 700, 1<<40, 1<<64-1)
+
+	assert.Equal(t, []uint64{0, 700, 1 << 40, 1<<64 - 1}, iterable.Values[uint64](s))
+	assert.Equal(t, []uint64{1<<64 - 1, 1 << 40, 700, 0}, iterable.Values[uint64](iterable.Reverse[uint64](s)))
+
+	next, ok := s.NextSet(701)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1<<40), next)
+
+	prev, ok := s.PrevSet(1<<40 - 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(700), prev)
+
+	small := New[uint8](3, 200)
+	assert.Equal(t, []uint8{200, 3}, iterable.Values[uint8](iterable.Reverse[uint8](small)))
+	assert.Equal(t, "{3, 200}", small.String())
+	assert.Empty(t, iterable.Values[uint8](iterable.Reverse[uint8](Bitset[uint8]{})))
+}
+
+func TestConcurrent(t *testing.T) {
+	base := New[uint](1, 2, 3)
+
+	var wg sync.WaitGroup
+	for i := uint(0); i < 8; i++ {
+		wg.Add(1)
+		go func(i uint) {
+			defer wg.Done()
+			s := base
+			for v := uint(0); v < 100; v++ {
+				s = s.With(1000*(i+1) + v)
+				assert.True(t, base.Get(2))
+			}
+			assert.Equal(t, uint(103), s.Pop())
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, uint(3), base.Pop())
+}
+
+func TestEncoding(t *testing.T) {
+	s := New[uint](1, 3, 5, 6, 7, 1_000_000)
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "[1,3,5,6,7,1000000]", string(data))
+
+	var decoded Bitset[uint]
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, iterable.Values[uint](s), iterable.Values[uint](decoded))
+
+	prev := decoded
+	assert.Error(t, decoded.UnmarshalText([]byte("nope")))
+	assert.Equal(t, prev, decoded, "unchanged on error")
+
+	assert.NoError(t, decoded.UnmarshalText([]byte("2-4")))
+	assert.Equal(t, []uint{2, 3, 4}, iterable.Values[uint](decoded))
+	assert.Equal(t, []uint{1, 3, 5, 6, 7, 1_000_000}, iterable.Values[uint](prev), "earlier versions are unaffected")
+}
+
+func TestInspect(t *testing.T) {
+	s := New[uint](1, 2, 3, 600)
+	st := s.Stats()
+	assert.Equal(t, uint(4), st.Pop)
+	assert.Equal(t, 2, st.Runs)
+	assert.Equal(t, s.Bytes(), st.Bytes)
+	assert.Greater(t, s.Bytes(), 2*leafWords*8)
+	assert.Equal(t, 1024, s.Cap())
+
+	// measured from the leaves' words, matching a member by member count
+	s, _ = s.Update(func(tx bitset.Tx[uint]) error {
+		tx.SetRange(60, 130)
+		tx.SetRange(1<<20, 1<<20+511)
+		return nil
+	})
+	it, _ := s.Iterate()
+	assert.Equal(t, bitset.Measure[uint](s.Bytes(), it), s.Stats())
+}
+
+func TestUpdate(t *testing.T) {
+	s := New[uint](1, 2, 3)
+
+	next, err := s.Update(func(tx bitset.Tx[uint]) error {
+		tx.Unset(2)
+		tx.SetRange(1000, 1002)
+		assert.False(t, tx.Get(2))
+		assert.True(t, tx.Get(1001))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 3, 1000, 1001, 1002}, iterable.Values[uint](next))
+	assert.Equal(t, []uint{1, 2, 3}, iterable.Values[uint](s), "the original is unchanged")
+
+	invalid := errors.New("invalid")
+	same, err := next.Update(func(tx bitset.Tx[uint]) error {
+		tx.UnsetRange(0, 5000)
+		return invalid
+	})
+	assert.ErrorIs(t, err, invalid)
+	assert.Equal(t, next, same)
+
+	// ranges are applied a leaf at a time, rather than a value at a time
+	wide, err := New[uint32](3, 7).Update(func(tx bitset.Tx[uint32]) error {
+		tx.UnsetRange(0, ^uint32(0))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), wide.Pop())
+
+	wider, err := New[uint64](3, 1<<40, 1<<62).Update(func(tx bitset.Tx[uint64]) error {
+		tx.SetRange(100, 100_000)
+		tx.UnsetRange(4, ^uint64(0))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3}, iterable.Values[uint64](wider))
+
+	filled, err := New[uint](1).Update(func(tx bitset.Tx[uint]) error {
+		tx.SetRange(500, 100_000)
+		tx.UnsetRange(600, 99_000)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1+100+1000), filled.Pop())
+	assert.True(t, filled.Get(599) && filled.Get(99_001))
+	assert.False(t, filled.Get(600) || filled.Get(99_000))
+}
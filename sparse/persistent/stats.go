@@ -0,0 +1,53 @@
+package persistent
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Bytes implements bitset.Inspect: every node of this version, including those it shares with others.
+func (s Bitset[V]) Bytes() int {
+	return int(unsafe.Sizeof(s)) + s.root.bytes()
+}
+
+func (n *node) bytes() int {
+	if n == nil {
+		return 0
+	}
+	bytes := int(unsafe.Sizeof(*n)) + cap(n.words)*8 + cap(n.kids)*int(unsafe.Sizeof(n))
+	for _, k := range n.kids {
+		bytes += k.bytes()
+	}
+	return bytes
+}
+
+// Stats implements bitset.Inspect. Runs are found a word at a time, leaf by leaf.
+func (s Bitset[V]) Stats() bitset.Stats[V] {
+	return bitset.MeasureWords[V](s.Bytes(), func(yield func(i, word uint64)) {
+		if s.root != nil {
+			s.root.leaves(s.height, 0, yield)
+		}
+	})
+}
+
+// leaves calls yield with each non-empty word under n, of height h, which starts at base, in ascending order.
+func (n *node) leaves(h, base uint, yield func(i, word uint64)) {
+	if h == 0 {
+		for i, w := range n.words {
+			if w != 0 {
+				yield(uint64(base/64+uint(i)), w)
+			}
+		}
+		return
+	}
+
+	width := span(h - 1)
+	for i, k := range n.kids {
+		if k != nil {
+			k.leaves(h-1, base+uint(i)*width, yield)
+		}
+	}
+}
+
+var _ bitset.Inspect[uint] = Bitset[uint]{}
@@ -0,0 +1,55 @@
+package persistent
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Update returns a version of the set with the modifications made by fn, or the set itself along with fn's error.
+// The modifications are made by a single editor, so each node along their paths is copied at most once.
+func (s Bitset[V]) Update(fn func(tx bitset.Tx[V]) error) (Bitset[V], error) {
+	tx := bitset.NewBatch(s.Get)
+	if err := fn(tx); err != nil {
+		return s, err
+	}
+
+	e := editor{}
+	tx.Replay(
+		func(lo, hi V) { s.tree = e.setRange(s.tree, uint(lo), uint(hi)) },
+		func(lo, hi V) { s.tree = e.unsetRange(s.tree, uint(lo), uint(hi)) },
+	)
+	return s, nil
+}
+
+// setRange adds every value in [lo, hi], a leaf at a time.
+func (e *editor) setRange(t tree, lo, hi uint) tree {
+	for {
+		end := lo | (1<<leafBits - 1) // the last value in lo's leaf
+		if end >= hi {
+			return e.setLeaf(t, lo, hi, true)
+		}
+		t = e.setLeaf(t, lo, end, true)
+		lo = end + 1
+	}
+}
+
+// unsetRange removes every value in [lo, hi], a leaf at a time. Only the leaves with members in the range are
+// visited, so its cost doesn't depend on the width of the range.
+func (e *editor) unsetRange(t tree, lo, hi uint) tree {
+	for t.covers(lo) {
+		if last := span(t.height) - 1; hi > last {
+			hi = last // nothing's stored beyond the root. at full height, this is the largest uint.
+		}
+
+		v, ok := next(t.root, t.height, 0, lo)
+		if !ok || v > hi {
+			break
+		}
+		end := v | (1<<leafBits - 1)
+		if end >= hi {
+			return e.setLeaf(t, v, hi, false)
+		}
+		t = e.setLeaf(t, v, end, false)
+		lo = end + 1
+	}
+	return t
+}
@@ -8,8 +8,11 @@ import (
 	"github.com/zblach/go-bitset/sparse/range/sparse_set"
 )
 
-// Iterate implements bitset.Inspect
+// Iterate implements iterable.Iterable
 func (s *Bitset[V]) Iterate() (iterable.Iter[V], uint) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
 	it := &Iterator[V]{
 		b: &Bitset[V]{
 			lock: &sync.RWMutex{},
@@ -43,11 +46,66 @@ func (it *Iterator[V]) Next() (V, bool) {
 	}
 
 	val := it.setRange.Start
-	it.setRange.Start++
+	if val == it.setRange.End {
+		// exhausted. incrementing could overflow, so reset to the illegal range instead.
+		it.setRange = *sparse_set.NewRange[V](1, 0)
+	} else {
+		it.setRange.Start++
+	}
+	return val, true
+}
+
+// IterateReverse implements iterable.Reversible
+func (s *Bitset[V]) IterateReverse() (iterable.Iter[V], uint) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	it := &ReverseIterator[V]{
+		b: &Bitset[V]{
+			lock: &sync.RWMutex{},
+			sets: make(sparse_set.Set[V], len(s.sets)),
+		},
+		setIndex: len(s.sets) - 1,
+	}
+	copy(it.b.sets, s.sets)
+
+	return it, s.pop
+}
+
+// ReverseIterator walks the ranges of a bitset backwards, enumerating it in descending order.
+type ReverseIterator[V bitset.Value] struct {
+	b *Bitset[V]
+
+	setIndex int
+	next     V
+	inRange  bool // next is within sets[setIndex]
+}
+
+func (it *ReverseIterator[V]) Next() (V, bool) {
+	it.b.lock.Lock()
+	defer it.b.lock.Unlock()
+
+	if !it.inRange {
+		if it.setIndex < 0 {
+			return 0, false
+		}
+		it.next = it.b.sets[it.setIndex].End
+		it.inRange = true
+	}
+
+	val := it.next
+	if val == it.b.sets[it.setIndex].Start {
+		it.inRange = false
+		it.setIndex--
+	} else {
+		it.next--
+	}
 	return val, true
 }
 
 var (
-	_ iterable.Iter[uint]     = (*Iterator[uint])(nil)
-	_ iterable.Iterable[rune] = (*Bitset[rune])(nil)
+	_ iterable.Iter[uint]       = (*Iterator[uint])(nil)
+	_ iterable.Iter[uint]       = (*ReverseIterator[uint])(nil)
+	_ iterable.Iterable[rune]   = (*Bitset[rune])(nil)
+	_ iterable.Reversible[rune] = (*Bitset[rune])(nil)
 )
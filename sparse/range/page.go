@@ -0,0 +1,52 @@
+package rangeset
+
+import (
+	"sort"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Version is incremented every time the bitset is modified.
+func (s *Bitset[V]) Version() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.version
+}
+
+// Page implements bitset.Pager
+func (s *Bitset[V]) Page(after bitset.Cursor[V], limit int) (vals []V, next bitset.Cursor[V], done bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	from, ok := after.Start()
+	if !ok {
+		return nil, after.Advance(nil, s.version), true
+	}
+
+	// first range which ends at or after from
+	i := sort.Search(len(s.sets), func(i int) bool {
+		return s.sets[i].End >= from
+	})
+	for ; i < len(s.sets); i++ {
+		r := s.sets[i]
+		if r.Start > from {
+			from = r.Start
+		}
+		for v := from; limit <= 0 || len(vals) < limit; v++ {
+			vals = append(vals, v)
+			if v == r.End {
+				break
+			}
+		}
+		if limit > 0 && len(vals) == limit {
+			break
+		}
+	}
+
+	done = i >= len(s.sets) || // ran out of ranges
+		(i == len(s.sets)-1 && vals[len(vals)-1] == s.sets[i].End) // stopped exactly at the end of the last range
+	return vals, after.Advance(vals, s.version), done
+}
+
+var _ bitset.Pager[uint] = (*Bitset[uint])(nil)
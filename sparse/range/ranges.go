@@ -0,0 +1,34 @@
+package rangeset
+
+// SetRange sets every value in [lo, hi], coalescing with the ranges it overlaps or touches.
+func (s *Bitset[V]) SetRange(lo, hi V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.setRange(lo, hi)
+}
+
+// UnsetRange unsets every value in [lo, hi].
+func (s *Bitset[V]) UnsetRange(lo, hi V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.unsetRange(lo, hi)
+}
+
+func (s *Bitset[V]) setRange(lo, hi V) {
+	s.own()
+	if added := s.sets.InsertRange(lo, hi); added > 0 {
+		s.pop += uint(added)
+		s.version++
+		s.waiters.Notify()
+	}
+}
+
+func (s *Bitset[V]) unsetRange(lo, hi V) {
+	s.own()
+	if removed := s.sets.RemoveRange(lo, hi); removed > 0 {
+		s.pop -= uint(removed)
+		s.version++
+	}
+}
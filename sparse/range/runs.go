@@ -0,0 +1,128 @@
+package rangeset
+
+import (
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+	"github.com/zblach/go-bitset/sparse/range/sparse_set"
+)
+
+// ranges is a copy of the bitset's ranges, for iterators to walk.
+func (s *Bitset[V]) ranges() sparse_set.Set[V] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	sets := make(sparse_set.Set[V], len(s.sets))
+	copy(sets, s.sets)
+	return sets
+}
+
+// IterateClear implements iterable.Runnable. Gaps are read directly from the stored ranges.
+func (s *Bitset[V]) IterateClear(limit V) (iterable.Iter[V], uint) {
+	it := &ClearIterator[V]{
+		lock:  &sync.RWMutex{},
+		sets:  s.ranges(),
+		limit: limit,
+	}
+
+	// counted one short, so that every value up to the largest doesn't overflow
+	size, covered := uint(limit), false
+	for _, r := range it.sets {
+		if r.Start > limit {
+			break
+		}
+		end := r.End
+		if end > limit {
+			end = limit
+		}
+		size -= uint(end-r.Start) + 1
+		covered = true
+	}
+	if size++; size == 0 && !covered {
+		size-- // every value, which doesn't fit
+	}
+	return it, size
+}
+
+// ClearIterator enumerates the gaps between ranges.
+type ClearIterator[V bitset.Value] struct {
+	lock *sync.RWMutex
+	sets sparse_set.Set[V]
+
+	setIndex int
+	pos      V
+	limit    V
+	done     bool
+}
+
+func (it *ClearIterator[V]) Next() (V, bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	for !it.done {
+		if it.setIndex < len(it.sets) && it.sets[it.setIndex].Contains(it.pos) {
+			// jump over this range
+			end := it.sets[it.setIndex].End
+			it.setIndex++
+			if end >= it.limit {
+				it.done = true
+				break
+			}
+			it.pos = end + 1
+			continue
+		}
+
+		val := it.pos
+		if val == it.limit {
+			it.done = true
+		} else {
+			it.pos++
+		}
+		return val, true
+	}
+	return 0, false
+}
+
+// Runs implements iterable.Runnable. Set runs are the stored ranges, and unset runs are the gaps between them.
+func (s *Bitset[V]) Runs() iterable.Iter[iterable.Run[V]] {
+	return &RunIterator[V]{
+		lock: &sync.RWMutex{},
+		sets: s.ranges(),
+	}
+}
+
+// RunIterator enumerates the ranges of a bitset, and the gaps between them.
+type RunIterator[V bitset.Value] struct {
+	lock *sync.RWMutex
+	sets sparse_set.Set[V]
+
+	setIndex int
+	pos      V
+}
+
+func (it *RunIterator[V]) Next() (iterable.Run[V], bool) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	if it.setIndex >= len(it.sets) {
+		return iterable.Run[V]{}, false
+	}
+
+	r := it.sets[it.setIndex]
+	if it.pos < r.Start {
+		gap := iterable.Run[V]{Start: it.pos, End: r.Start - 1}
+		it.pos = r.Start
+		return gap, true
+	}
+
+	it.setIndex++
+	it.pos = r.End + 1
+	return iterable.Run[V]{Start: r.Start, End: r.End, Set: true}, true
+}
+
+var (
+	_ iterable.Iter[uint]               = (*ClearIterator[uint])(nil)
+	_ iterable.Iter[iterable.Run[uint]] = (*RunIterator[uint])(nil)
+	_ iterable.Runnable[rune]           = (*Bitset[rune])(nil)
+)
@@ -0,0 +1,74 @@
+package rangeset
+
+import (
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+	"github.com/zblach/go-bitset/sparse/range/sparse_set"
+)
+
+// Snapshot returns a read-only view of the bitset as it is now, in constant time. Unlike Copy, the ranges aren't
+// duplicated up front; the bitset copies them the first time it's modified afterwards, leaving the snapshot with
+// the originals.
+func (s *Bitset[V]) Snapshot() *Snapshot[V] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.shared = true
+	return &Snapshot[V]{
+		sets:    s.sets,
+		pop:     s.pop,
+		version: s.version,
+	}
+}
+
+// own copies the ranges before they're modified, if a snapshot refers to them.
+func (s *Bitset[V]) own() {
+	if s.shared {
+		s.sets = append(make(sparse_set.Set[V], 0, len(s.sets)), s.sets...)
+		s.shared = false
+	}
+}
+
+// Snapshot is the members of a bitset at one point. It's unaffected by later modifications to the bitset, and is
+// safe for concurrent use.
+type Snapshot[V bitset.Value] struct {
+	sets    sparse_set.Set[V] // never modified
+	pop     uint
+	version uint64
+}
+
+// Get returns whether index was a member.
+func (s *Snapshot[V]) Get(index V) bool {
+	return s.sets.Contains(index)
+}
+
+// Pop returns the number of members.
+func (s *Snapshot[V]) Pop() uint {
+	return s.pop
+}
+
+// Version returns the bitset's version when the snapshot was taken.
+func (s *Snapshot[V]) Version() uint64 {
+	return s.version
+}
+
+// Iterate implements iterable.Iterable. The ranges are shared with the iterator, rather than copied.
+func (s *Snapshot[V]) Iterate() (iterable.Iter[V], uint) {
+	return &Iterator[V]{
+		b: &Bitset[V]{
+			lock: &sync.RWMutex{},
+			sets: s.sets,
+		},
+		setRange: *sparse_set.NewRange[V](1, 0),
+	}, s.pop
+}
+
+// String implements fmt.Stringer, e.g. {1, 3, 5-9}.
+func (s *Snapshot[V]) String() string {
+	it, _ := s.Iterate()
+	return bitset.String[V](it)
+}
+
+var _ iterable.Iterable[uint] = (*Snapshot[uint])(nil)
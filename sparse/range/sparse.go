@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/wait"
 	"github.com/zblach/go-bitset/mixin/logical"
 	"github.com/zblach/go-bitset/sparse/range/sparse_set"
 )
@@ -13,8 +14,11 @@ type Bitset[V bitset.Value] struct {
 
 	lock *sync.RWMutex
 
-	sets sparse_set.Set[V]
-	pop  uint
+	sets    sparse_set.Set[V]
+	pop     uint
+	version uint64      // incremented on every modification
+	shared  bool        // sets is referenced by a snapshot, so must be copied before it's modified
+	waiters wait.Signal // woken as members are added
 }
 
 // And implements bitset.Logical
@@ -77,18 +81,27 @@ func (a *Bitset[V]) Or(b *Bitset[V]) (aOrB *Bitset[V]) {
 	return
 }
 
-// Cap implements bitset.Inspect
+// Cap implements bitset.Inspect. It's the number of ranges which can be stored without reallocating.
 func (s *Bitset[V]) Cap() int {
-	return int(s.pop)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return cap(s.sets)
 }
 
-// Len implements bitset.Inspect
+// Len implements bitset.Inspect. It's the number of ranges stored, which may be far fewer than the members.
 func (s *Bitset[V]) Len() int {
-	return int(s.pop)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.sets)
 }
 
 // Pop implements bitset.Inspect
 func (s *Bitset[V]) Pop() uint {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
 	return s.pop
 }
 
@@ -104,6 +117,9 @@ func (s *Bitset[V]) Clear() {
 	defer s.lock.Unlock()
 
 	s.sets = make(sparse_set.Set[V], 0)
+	s.shared = false
+	s.pop = 0
+	s.version++
 }
 
 func (s *Bitset[V]) Copy() *Bitset[V] {
@@ -112,6 +128,7 @@ func (s *Bitset[V]) Copy() *Bitset[V] {
 
 	clone := New[V]()
 	clone.pop = s.pop
+	clone.sets = make(sparse_set.Set[V], len(s.sets))
 	copy(clone.sets, s.sets)
 
 	return clone
@@ -122,13 +139,7 @@ func (s *Bitset[V]) Get(index V) bool {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
-	for _, st := range s.sets {
-		if st.Contains(index) {
-			return true
-		}
-	}
-
-	return false
+	return s.sets.Contains(index)
 }
 
 // Set implements bitset.Bitset
@@ -136,11 +147,17 @@ func (s *Bitset[V]) Set(indices ...V) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	s.own()
+	pop := s.pop
 	for _, index := range indices {
 		if s.sets.Insert(index) {
 			s.pop++
 		}
 	}
+	if s.pop != pop {
+		s.version++
+		s.waiters.Notify()
+	}
 }
 
 // Unset implements bitset.Bitset
@@ -148,11 +165,16 @@ func (s *Bitset[V]) Unset(indices ...V) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	s.own()
+	pop := s.pop
 	for _, index := range indices {
 		if s.sets.Remove(index) {
 			s.pop--
 		}
 	}
+	if s.pop != pop {
+		s.version++
+	}
 }
 
 var (
@@ -1,6 +1,10 @@
 package sparse_set
 
-import "golang.org/x/exp/constraints"
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
 
 // sparseSet is an ordered series of disjoint sparseRange items. overlapping ranges are coalesced.
 type Set[V constraints.Integer] []Range[V]
@@ -12,7 +16,7 @@ func (s *Set[V]) Insert(val V) bool {
 			// val exists already
 			return false
 
-		case r.Start-1 == val:
+		case val < r.Start && r.Start-1 == val:
 			// we're not contained by the previous set, but this one can be extended forward
 			(*s)[i].Start -= 1
 			return true
@@ -26,7 +30,7 @@ func (s *Set[V]) Insert(val V) bool {
 			(*s)[i] = Range[V]{val, val}
 			return true
 
-		case r.End+1 == val:
+		case val > r.End && r.End+1 == val:
 			// extend this element, and check if it collides into the next element (if it exists)
 			(*s)[i].End += 1
 			if i+1 == len(*s) {
@@ -51,28 +55,24 @@ func (s *Set[V]) Remove(val V) bool {
 	for i, r := range *s {
 		switch {
 		case r.Start > val:
-			return false
 			// element not present to be removed. no-op.
+			return false
 
 		case r.Start == val:
-			// r can have start modified
-			(*s)[i].Start += 1
-			if (*s)[i].Start > r.End {
-				// remove invalid element.
-				if i == 0 {
-					(*s) = (*s)[1:]
-				} else {
-					for j := i; j < len(*s)-1; j++ {
-						(*s)[j] = (*s)[j+1]
-					}
-					(*s) = (*s)[:len(*s)-1]
-				}
+			if r.Start == r.End {
+				// r only held val. compared before incrementing, so that Start can't wrap around.
+				(*s) = append((*s)[:i], (*s)[i+1:]...)
+			} else {
+				// r can have start modified
+				(*s)[i].Start += 1
 			}
+			return true
 
 		case r.End == val:
 			// r can have end modified
 			(*s)[i].End -= 1
 			// if the element was invalid here, then it was one element long, and would have been caught by the previous case.
+			return true
 
 		case r.Contains(val):
 			// split r into two parts, excluding val
@@ -84,7 +84,82 @@ func (s *Set[V]) Remove(val V) bool {
 
 			(*s)[i+1].Start = val + 1
 			(*s)[i+1].End = r.End
+			return true
 		}
 	}
-	return true
+	return false
+}
+
+// InsertRange adds [lo, hi], coalescing it with every range it overlaps or touches. It returns the number of values added.
+func (s *Set[V]) InsertRange(lo, hi V) (added uint64) {
+	if lo > hi {
+		return 0
+	}
+
+	// [i, j) are the ranges which overlap or touch [lo, hi]
+	i := sort.Search(len(*s), func(k int) bool { r := (*s)[k]; return r.End >= lo || r.End+1 == lo })
+	j := sort.Search(len(*s), func(k int) bool { r := (*s)[k]; return r.Start > hi && r.Start-1 != hi })
+
+	merged := Range[V]{lo, hi}
+	added = uint64(hi-lo) + 1
+	for _, r := range (*s)[i:j] {
+		added -= overlap(r, lo, hi)
+		if r.Start < merged.Start {
+			merged.Start = r.Start
+		}
+		if r.End > merged.End {
+			merged.End = r.End
+		}
+	}
+
+	*s = append((*s)[:i], append([]Range[V]{merged}, (*s)[j:]...)...)
+	return added
+}
+
+// RemoveRange removes [lo, hi], trimming or splitting the ranges it overlaps. It returns the number of values removed.
+func (s *Set[V]) RemoveRange(lo, hi V) (removed uint64) {
+	if lo > hi {
+		return 0
+	}
+
+	// [i, j) are the ranges which overlap [lo, hi]
+	i := sort.Search(len(*s), func(k int) bool { return (*s)[k].End >= lo })
+	j := sort.Search(len(*s), func(k int) bool { return (*s)[k].Start > hi })
+	if i >= j {
+		return 0
+	}
+
+	var rest []Range[V]
+	if first := (*s)[i]; first.Start < lo {
+		rest = append(rest, Range[V]{first.Start, lo - 1})
+	}
+	if last := (*s)[j-1]; last.End > hi {
+		rest = append(rest, Range[V]{hi + 1, last.End})
+	}
+	for _, r := range (*s)[i:j] {
+		removed += overlap(r, lo, hi)
+	}
+
+	*s = append((*s)[:i], append(rest, (*s)[j:]...)...)
+	return removed
+}
+
+// overlap is the number of values r has in common with [lo, hi].
+func overlap[V constraints.Integer](r Range[V], lo, hi V) uint64 {
+	if r.Start > lo {
+		lo = r.Start
+	}
+	if r.End < hi {
+		hi = r.End
+	}
+	if lo > hi {
+		return 0
+	}
+	return uint64(hi-lo) + 1
+}
+
+// Contains reports whether val is in any of the ranges, by binary search.
+func (s Set[V]) Contains(val V) bool {
+	i := sort.Search(len(s), func(k int) bool { return s[k].End >= val })
+	return i < len(s) && s[i].Start <= val
 }
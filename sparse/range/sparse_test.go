@@ -1,9 +1,12 @@
 package rangeset
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
 	"github.com/zblach/go-bitset/sparse/range/sparse_set"
 )
 
@@ -136,3 +139,183 @@ func TestLogical(t *testing.T) {
 	assert.EqualValues(t, sparse_set.Set[uint]{{6, 6}, {8, 8}}, aAndB.sets)
 	assert.EqualValues(t, sparse_set.Set[uint]{{1, 4}, {6, 8}}, aOrB.sets)
 }
+
+func TestPage(t *testing.T) {
+	s := New[uint]()
+	s.Set(0, 1, 64, 65, 200, 201, 202)
+
+	vals, next, done := s.Page(bitset.Cursor[uint]{}, 3)
+	assert.Equal(t, []uint{0, 1, 64}, vals)
+	assert.False(t, done)
+
+	vals, next, done = s.Page(next, 3)
+	assert.Equal(t, []uint{65, 200, 201}, vals)
+	assert.False(t, done)
+	assert.False(t, next.Changed())
+
+	s.Unset(65)
+	vals, next, done = s.Page(next, 1)
+	assert.Equal(t, []uint{202}, vals)
+	assert.True(t, done)
+	assert.True(t, next.Changed())
+
+	vals, _, done = s.Page(bitset.After[uint](1), 0)
+	assert.Equal(t, []uint{64, 200, 201, 202}, vals)
+	assert.True(t, done)
+
+	vals, _, done = s.Page(bitset.After[uint](202), 5)
+	assert.Empty(t, vals)
+	assert.True(t, done)
+}
+
+func TestRangeReverseIterator(t *testing.T) {
+	s := New[uint]()
+	s.Set(0, 1, 2, 4, 5, 6, 7, 8, 12)
+
+	assert.EqualValues(t, []uint{12, 8, 7, 6, 5, 4, 2, 1, 0}, iterable.Values(iterable.Reverse[uint](s)))
+}
+
+func TestSparseSetInsertRemoveRange(t *testing.T) {
+	r := func(start, end uint) sparse_set.Range[uint] { return sparse_set.Range[uint]{Start: start, End: end} }
+	ss := sparse_set.Set[uint]{r(1, 2), r(4, 8), r(11, 15)}
+
+	assert.Equal(t, uint64(0), ss.InsertRange(5, 7))
+	assert.Equal(t, uint64(1), ss.InsertRange(3, 3))
+	assert.Equal(t, sparse_set.Set[uint]{r(1, 8), r(11, 15)}, ss)
+	// touching ranges coalesce
+
+	assert.Equal(t, uint64(8), ss.InsertRange(0, 20))
+	assert.Equal(t, sparse_set.Set[uint]{r(0, 20)}, ss)
+
+	assert.Equal(t, uint64(5), ss.RemoveRange(5, 9))
+	assert.Equal(t, sparse_set.Set[uint]{r(0, 4), r(10, 20)}, ss)
+	// split
+
+	assert.Equal(t, uint64(0), ss.RemoveRange(5, 9))
+	assert.Equal(t, uint64(8), ss.RemoveRange(0, 12))
+	assert.Equal(t, sparse_set.Set[uint]{r(13, 20)}, ss)
+
+	assert.Equal(t, uint64(2), ss.InsertRange(22, 23))
+	assert.Equal(t, sparse_set.Set[uint]{r(13, 20), r(22, 23)}, ss)
+	assert.Equal(t, uint64(10), ss.RemoveRange(0, 100))
+	assert.Empty(t, ss)
+}
+
+func TestSetRange(t *testing.T) {
+	s := New[uint8]()
+	s.Set(3, 200)
+	s.SetRange(250, 255)
+	s.SetRange(0, 10)
+	assert.Equal(t, uint(18), s.Pop())
+
+	s.UnsetRange(5, 252)
+	assert.Equal(t, []uint8{0, 1, 2, 3, 4, 253, 254, 255}, iterable.Values[uint8](s))
+	assert.Equal(t, uint(8), s.Pop())
+}
+
+func TestSparseSetInsert_Wraparound(t *testing.T) {
+	s := New[uint16]()
+	s.Set(0, 1, 2, 65535)
+	assert.Equal(t, []uint16{0, 1, 2, 65535}, iterable.Values[uint16](s))
+
+	s.Set(65534)
+	assert.Equal(t, []uint16{0, 1, 2, 65534, 65535}, iterable.Values[uint16](s))
+}
+
+func TestSparseSetRemove_Wraparound(t *testing.T) {
+	s := New[uint16]()
+	s.Set(0, 65535)
+	s.Unset(65535)
+	assert.Equal(t, []uint16{0}, iterable.Values[uint16](s))
+	assert.Equal(t, uint(1), s.Pop())
+}
+
+func TestInspect(t *testing.T) {
+	s := New[uint]()
+	s.SetRange(0, 999)
+	s.Set(2000)
+	assert.Equal(t, uint(1001), s.Pop())
+	assert.Equal(t, 2, s.Len(), "ranges, not members")
+	assert.GreaterOrEqual(t, s.Cap(), 2)
+
+	c := s.Copy()
+	s.Clear()
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, []uint{998, 999, 2000}, iterable.Values[uint](iterable.Skip[uint](c, 998)))
+}
+
+func TestSnapshot(t *testing.T) {
+	s := New[uint]()
+	s.SetRange(1, 10)
+
+	snap := s.Snapshot()
+	s.Unset(5)
+	s.SetRange(20, 30)
+	later := s.Snapshot()
+	s.UnsetRange(0, 100)
+
+	assert.True(t, snap.Get(5))
+	assert.False(t, snap.Get(20))
+	assert.Equal(t, "{1-10}", snap.String())
+	assert.Equal(t, uint(10), snap.Pop())
+	assert.Equal(t, "{1-4, 6-10, 20-30}", later.String())
+	assert.Equal(t, uint(0), s.Pop())
+}
+
+func TestCopy(t *testing.T) {
+	s := New[uint]()
+	s.SetRange(10, 20)
+	s.Set(30)
+
+	// Copy once copied into an empty slice, so copies had no members, despite their population
+	c := s.Copy()
+	c.Unset(15)
+	s.Clear()
+
+	assert.Equal(t, []uint{10, 11, 12, 13, 14, 16, 17, 18, 19, 20, 30}, iterable.Values[uint](c))
+	assert.Equal(t, uint(11), c.Pop())
+}
+
+func TestIterateClearSize(t *testing.T) {
+	s := New[uint]()
+	_, size := s.IterateClear(^uint(0))
+	assert.Equal(t, ^uint(0), size, "every value doesn't fit, so is clamped")
+
+	s.Set(5)
+	_, size = s.IterateClear(^uint(0))
+	assert.Equal(t, ^uint(0), size, "exactly")
+	_, size = s.IterateClear(10)
+	assert.Equal(t, uint(10), size)
+
+	full := New[uint8]()
+	full.SetRange(0, 255)
+	_, size = full.IterateClear(255)
+	assert.Equal(t, uint(0), size)
+}
+
+func TestVersion(t *testing.T) {
+	s := New[uint]()
+	s.Set(1, 2, 3, 10)
+	assert.Equal(t, uint64(1), s.Version(), "once per modification, not per value")
+	s.Set(1, 2)
+	assert.Equal(t, uint64(1), s.Version(), "no change")
+
+	s.Unset(1, 10, 50)
+	assert.Equal(t, uint64(2), s.Version())
+	s.Unset(50)
+	assert.Equal(t, uint64(2), s.Version())
+}
+
+func TestStringHuge(t *testing.T) {
+	s := New[uint]()
+	s.SetRange(0, 1<<34)
+	for i := uint(0); i < 100; i++ {
+		s.Set(1<<40 + 2*i)
+	}
+
+	// printed from the ranges, rather than from every member
+	assert.Equal(t, "{0-17179869184, 1099511627776, 1099511627778, … +98 more}", fmt.Sprintf("%.3v", s))
+	assert.Equal(t, "{0, 1, … +17179869283 more}", fmt.Sprintf("%.2d", s))
+	assert.Contains(t, s.String(), "… +37 more}")
+	assert.Equal(t, "…ffff", fmt.Sprintf("%.4x", s))
+}
@@ -0,0 +1,22 @@
+package rangeset
+
+import (
+	"unsafe"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/iterable"
+	"github.com/zblach/go-bitset/sparse/range/sparse_set"
+)
+
+// Bytes implements bitset.Inspect: the bitset, its lock, and its ranges up to their capacity.
+func (s *Bitset[V]) Bytes() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return int(unsafe.Sizeof(*s)+unsafe.Sizeof(*s.lock)) + cap(s.sets)*int(unsafe.Sizeof(sparse_set.Range[V]{}))
+}
+
+// Stats implements bitset.Inspect. It's computed from the ranges, without visiting each member.
+func (s *Bitset[V]) Stats() bitset.Stats[V] {
+	return iterable.Measure(s.Bytes(), s.Runs())
+}
@@ -0,0 +1,18 @@
+package rangeset
+
+import (
+	"github.com/zblach/go-bitset"
+)
+
+// Update calls fn with a transaction, and applies its modifications atomically if fn returns nil. See bitset.Tx.
+func (s *Bitset[V]) Update(fn func(tx bitset.Tx[V]) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tx := bitset.NewBatch(s.sets.Contains)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	tx.Replay(s.setRange, s.unsetRange)
+	return nil
+}
@@ -0,0 +1,100 @@
+package bitset
+
+import (
+	mb "math/bits"
+)
+
+// Stats summarizes the members of a set, and its footprint.
+type Stats[V Value] struct {
+	Pop   uint
+	Bytes int // as returned by Inspect.Bytes
+
+	Min, Max V // the smallest and largest members, when Pop > 0
+
+	Runs  int // maximal runs of consecutive members
+	Words int // 64-bit words holding at least one member; the footprint of the members as a minimal dense bitmap
+
+	// Density is the fraction of the values from Min to Max which are members. It's 1 for a single run.
+	Density float64
+	// Fragmentation is 0 when the members form a single run, and 1 when no two members are adjacent.
+	Fragmentation float64
+}
+
+// Measure computes Stats from the ascending members of a set. bytes is the set's Bytes.
+func Measure[V Value](bytes int, members Members[V]) Stats[V] {
+	var m measure[V]
+	for v, ok := members.Next(); ok; v, ok = members.Next() {
+		m.add(v, v)
+	}
+	return m.stats(bytes)
+}
+
+// MeasureRuns computes Stats from the ascending, inclusive runs of members of a set, as returned by next until it
+// reports false. Adjacent runs are merged, so backends needn't return maximal runs.
+func MeasureRuns[V Value](bytes int, next func() (start, end V, ok bool)) Stats[V] {
+	var m measure[V]
+	for start, end, ok := next(); ok; start, end, ok = next() {
+		m.add(start, end)
+	}
+	return m.stats(bytes)
+}
+
+// MeasureWords computes Stats from the 64-bit words of a set, where word i holds the members from 64i to 64i+63. each
+// must call yield with the words in ascending order of i, and may skip those which are empty.
+func MeasureWords[V Value](bytes int, each func(yield func(i, word uint64))) Stats[V] {
+	var m measure[V]
+	each(func(i, w uint64) {
+		for w != 0 {
+			lo := uint64(mb.TrailingZeros64(w))
+			n := uint64(mb.TrailingZeros64(^(w >> lo))) // the length of the run starting at lo
+			m.add(V(i*64+lo), V(i*64+lo+n-1))
+			if lo+n == 64 {
+				break
+			}
+			w &^= 1<<(lo+n) - 1
+		}
+	})
+	return m.stats(bytes)
+}
+
+// measure accumulates Stats, a run at a time.
+type measure[V Value] struct {
+	st Stats[V]
+}
+
+func (m *measure[V]) add(start, end V) {
+	st := &m.st
+
+	words := int(uint64(end)/64-uint64(start)/64) + 1
+	if st.Pop == 0 {
+		st.Min = start
+		st.Runs = 1
+	} else {
+		if start != st.Max+1 {
+			st.Runs++
+		}
+		if uint64(start)/64 == uint64(st.Max)/64 {
+			words-- // shared with the previous run
+		}
+	}
+
+	st.Words += words
+	if pop := add(uint64(st.Pop), span(start, end)); pop < uint64(^uint(0)) {
+		st.Pop = uint(pop)
+	} else {
+		st.Pop = ^uint(0) // every value of a uint, which doesn't fit
+	}
+	st.Max = end
+}
+
+func (m *measure[V]) stats(bytes int) Stats[V] {
+	st := m.st
+	st.Bytes = bytes
+	if st.Pop > 0 {
+		st.Density = float64(st.Pop) / (float64(st.Max-st.Min) + 1)
+	}
+	if st.Pop > 1 {
+		st.Fragmentation = float64(st.Runs-1) / float64(st.Pop-1)
+	}
+	return st
+}
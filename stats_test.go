@@ -0,0 +1,78 @@
+package bitset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasure(t *testing.T) {
+	st := set{[]uint{3, 4, 5, 6, 60, 63, 64, 200}}.Stats()
+	assert.Equal(t, Stats[uint]{
+		Pop:           8,
+		Bytes:         16,
+		Min:           3,
+		Max:           200,
+		Runs:          4,
+		Words:         3, // 0-63, 64-127 and 192-255
+		Density:       8.0 / 198,
+		Fragmentation: 3.0 / 7,
+	}, st)
+
+	assert.Equal(t, Stats[uint]{Bytes: 16}, set{}.Stats())
+	assert.Equal(t, Stats[uint]{Pop: 1, Bytes: 16, Min: 7, Max: 7, Runs: 1, Words: 1, Density: 1}, set{[]uint{7}}.Stats())
+}
+
+func TestMeasureRuns(t *testing.T) {
+	runs := [][2]uint8{{0, 9}, {10, 19}, {100, 255}} // the first two are adjacent
+	next := func() (uint8, uint8, bool) {
+		if len(runs) == 0 {
+			return 0, 0, false
+		}
+		r := runs[0]
+		runs = runs[1:]
+		return r[0], r[1], true
+	}
+
+	st := MeasureRuns(0, next)
+	assert.Equal(t, uint(176), st.Pop)
+	assert.Equal(t, 2, st.Runs)
+	assert.Equal(t, 4, st.Words)
+	assert.Equal(t, uint8(255), st.Max)
+	assert.InDelta(t, 176.0/256, st.Density, 1e-9)
+	assert.InDelta(t, 1.0/175, st.Fragmentation, 1e-9)
+}
+
+func TestMeasureWords(t *testing.T) {
+	st := MeasureWords[uint](0, func(yield func(i, word uint64)) {
+		yield(0, 0b1011)
+		yield(1, 1<<63)
+		yield(2, math.MaxUint64) // joined with the run above
+		yield(3, 1)
+		yield(10, 1<<5|1<<63)
+	})
+	assert.Equal(t, Stats[uint]{
+		Pop:           3 + 1 + 64 + 1 + 2,
+		Min:           0,
+		Max:           10*64 + 63,
+		Runs:          5,
+		Words:         5,
+		Density:       71.0 / 704,
+		Fragmentation: 4.0 / 70,
+	}, st)
+}
+
+func TestMeasureEverything(t *testing.T) {
+	done := false
+	st := MeasureRuns(0, func() (uint, uint, bool) {
+		if done {
+			return 0, 0, false
+		}
+		done = true
+		return 0, math.MaxUint, true
+	})
+	assert.Equal(t, uint(math.MaxUint), st.Pop, "saturated, rather than wrapped to 0")
+	assert.InDelta(t, 1, st.Density, 1e-9)
+	assert.Zero(t, st.Fragmentation)
+}
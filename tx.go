@@ -0,0 +1,109 @@
+package bitset
+
+// Tx is a batch of modifications to a set, made within a backend's Update.
+//
+// Modifications are recorded, and only applied to the set once the update's function returns without error;
+// otherwise, the set is left unchanged, and the error returned. Get reflects the set as modified so far by the
+// transaction. The backends which lock hold their write lock throughout an update, so its function must not use the
+// set itself.
+type Tx[V Value] interface {
+	Get(index V) bool
+
+	Set(indices ...V)
+	Unset(indices ...V)
+
+	SetRange(lo, hi V)
+	UnsetRange(lo, hi V)
+}
+
+// Batch is a Tx which records its modifications, for a backend to replay once the transaction commits.
+type Batch[V Value] struct {
+	get    func(V) bool // the members before the transaction
+	ops    []op[V]
+	last   map[V]int // the latest single-value op for each value modified by one
+	ranges []int     // the ops covering more than one value
+}
+
+// op sets or unsets every value in [lo, hi].
+type op[V Value] struct {
+	set    bool
+	lo, hi V
+}
+
+// NewBatch creates an empty batch over a set whose members are reported by get.
+// A backend calls it with its write lock held, and get must not take the lock.
+func NewBatch[V Value](get func(V) bool) *Batch[V] {
+	return &Batch[V]{get: get, last: map[V]int{}}
+}
+
+// Get implements Tx
+func (b *Batch[V]) Get(index V) bool {
+	latest, ok := b.last[index]
+	if !ok {
+		latest = -1
+	}
+	for i := len(b.ranges) - 1; i >= 0 && b.ranges[i] > latest; i-- {
+		if o := b.ops[b.ranges[i]]; o.lo <= index && index <= o.hi {
+			return o.set
+		}
+	}
+	if ok {
+		return b.ops[latest].set
+	}
+	return b.get(index)
+}
+
+// Set implements Tx
+func (b *Batch[V]) Set(indices ...V) {
+	for _, index := range indices {
+		b.record(op[V]{true, index, index})
+	}
+}
+
+// Unset implements Tx
+func (b *Batch[V]) Unset(indices ...V) {
+	for _, index := range indices {
+		b.record(op[V]{false, index, index})
+	}
+}
+
+// SetRange implements Tx
+func (b *Batch[V]) SetRange(lo, hi V) {
+	if lo <= hi {
+		b.record(op[V]{true, lo, hi})
+	}
+}
+
+// UnsetRange implements Tx
+func (b *Batch[V]) UnsetRange(lo, hi V) {
+	if lo <= hi {
+		b.record(op[V]{false, lo, hi})
+	}
+}
+
+func (b *Batch[V]) record(o op[V]) {
+	if o.lo == o.hi {
+		b.last[o.lo] = len(b.ops)
+	} else {
+		b.ranges = append(b.ranges, len(b.ops))
+	}
+	b.ops = append(b.ops, o)
+}
+
+// Len is the number of modifications recorded.
+func (b *Batch[V]) Len() int {
+	return len(b.ops)
+}
+
+// Replay calls set or unset with every modification, in the order they were made.
+func (b *Batch[V]) Replay(set, unset func(lo, hi V)) {
+	for _, o := range b.ops {
+		if o.set {
+			set(o.lo, o.hi)
+		} else {
+			unset(o.lo, o.hi)
+		}
+	}
+}
+
+var _ Tx[uint] = (*Batch[uint])(nil)
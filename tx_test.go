@@ -0,0 +1,37 @@
+package bitset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	before := map[uint]bool{1: true, 2: true, 50: true}
+	b := NewBatch(func(v uint) bool { return before[v] })
+
+	b.Set(3)
+	b.Unset(1)
+	assert.True(t, b.Get(2), "unmodified")
+	assert.True(t, b.Get(3))
+	assert.False(t, b.Get(1))
+
+	b.UnsetRange(0, 10)
+	assert.False(t, b.Get(3), "the range is more recent")
+	b.Set(4)
+	assert.True(t, b.Get(4), "the value is more recent")
+	b.SetRange(9, 5) // empty
+	assert.True(t, b.Get(50))
+
+	type op struct {
+		set    bool
+		lo, hi uint
+	}
+	var ops []op
+	b.Replay(
+		func(lo, hi uint) { ops = append(ops, op{true, lo, hi}) },
+		func(lo, hi uint) { ops = append(ops, op{false, lo, hi}) },
+	)
+	assert.Equal(t, []op{{true, 3, 3}, {false, 1, 1}, {false, 0, 10}, {true, 4, 4}}, ops)
+	assert.Equal(t, 4, b.Len())
+}
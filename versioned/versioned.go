@@ -0,0 +1,381 @@
+// Package versioned keeps the history of a set, for undo, redo and reading it as of an earlier version.
+//
+// A Bitset wraps a backend, and journals every modification made through it as a delta: the runs of values which it
+// actually set and unset. Each modification which changes anything is given the next version number, so versions
+// only increase, and are never reused, even once undone. Version 0 is the wrapped set as it was.
+//
+// History is linear. Undo and Redo move back and forth through it, and a modification made after an Undo discards
+// the versions which could have been redone. Compact bounds its length.
+package versioned
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/flip"
+	"github.com/zblach/go-bitset/iterable"
+	"github.com/zblach/go-bitset/sparse/persistent"
+)
+
+var (
+	ErrUnknownVersion = errors.New("versioned: version isn't in the history")
+	ErrCompacted      = errors.New("versioned: version has been compacted away")
+)
+
+// Backend is a set which can be versioned. Every backend in this module is one.
+type Backend[V bitset.Value] interface {
+	bitset.Bitset[V]
+	iterable.Iterable[V]
+}
+
+// ranger is implemented by backends which can set and unset a range of values at once.
+type ranger[V bitset.Value] interface {
+	SetRange(lo, hi V)
+	UnsetRange(lo, hi V)
+}
+
+// navigator is implemented by backends which can skip to their next member, and past it, like bits.
+type navigator[V bitset.Value] interface {
+	NextSet(from V) (V, bool)
+	NextClear(from V) (V, bool)
+}
+
+// runner is implemented by backends which can enumerate their runs.
+type runner[V bitset.Value] interface {
+	Runs() iterable.Iter[iterable.Run[V]]
+}
+
+// run is an inclusive range of values.
+type run[V bitset.Value] struct {
+	lo, hi V
+}
+
+// delta is a modification, from version prev to version.
+type delta[V bitset.Value] struct {
+	version, prev uint64
+	set, unset    []run[V] // ascending, and not adjacent
+}
+
+// Bitset is a set which journals its modifications.
+type Bitset[V bitset.Value] struct {
+	lock *sync.RWMutex
+
+	inner Backend[V]
+
+	done   []delta[V] // applied, oldest first
+	undone []delta[V] // undone, most recently undone last
+	base   uint64     // the oldest version which can be returned to
+	last   uint64     // the last version assigned
+}
+
+// New wraps inner, at version 0. It should only be modified through the returned Bitset from then on.
+func New[V bitset.Value](inner Backend[V]) *Bitset[V] {
+	return &Bitset[V]{
+		lock:  &sync.RWMutex{},
+		inner: inner,
+	}
+}
+
+// Version returns the current version.
+func (s *Bitset[V]) Version() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.version()
+}
+
+func (s *Bitset[V]) version() uint64 {
+	if len(s.done) == 0 {
+		return s.base
+	}
+	return s.done[len(s.done)-1].version
+}
+
+// Get implements bitset.Bitset
+func (s *Bitset[V]) Get(index V) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Get(index)
+}
+
+// Set implements bitset.Bitset. If any of the values weren't members, it makes a new version.
+func (s *Bitset[V]) Set(indices ...V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.record(runs(flip.Changed(s.inner.Get, indices, false)), nil)
+}
+
+// Unset implements bitset.Bitset. If any of the values were members, it makes a new version.
+func (s *Bitset[V]) Unset(indices ...V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.record(nil, runs(flip.Changed(s.inner.Get, indices, true)))
+}
+
+// SetRange adds every value in [lo, hi]. If any of them weren't members, it makes a new version, whose delta is the
+// runs between the existing members.
+func (s *Bitset[V]) SetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.record(gaps(lo, hi, s.members(lo, hi)), nil)
+}
+
+// UnsetRange removes every value in [lo, hi]. If any of them were members, it makes a new version, whose delta is
+// their runs.
+func (s *Bitset[V]) UnsetRange(lo, hi V) {
+	if lo > hi {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.record(nil, s.members(lo, hi))
+}
+
+// Clear implements bitset.Bitset. If there were any members, it makes a new version.
+func (s *Bitset[V]) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.record(nil, runs(iterable.Values[V](s.inner)))
+}
+
+// Iterate implements iterable.Iterable
+func (s *Bitset[V]) Iterate() (iterable.Iter[V], uint) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Iterate()
+}
+
+// members returns the runs of members within [lo, hi]. Backends which can navigate, or enumerate their runs, are read
+// a run at a time.
+func (s *Bitset[V]) members(lo, hi V) (rs []run[V]) {
+	if n, ok := s.inner.(navigator[V]); ok {
+		for v, ok := n.NextSet(lo); ok && v <= hi; v, ok = n.NextSet(v) {
+			end, ok := n.NextClear(v)
+			if !ok || end > hi {
+				return append(rs, run[V]{v, hi})
+			}
+			rs = append(rs, run[V]{v, end - 1})
+			v = end
+		}
+		return rs
+	}
+
+	if r, ok := s.inner.(runner[V]); ok {
+		it := r.Runs()
+		for m, ok := it.Next(); ok && m.Start <= hi; m, ok = it.Next() {
+			if !m.Set || m.End < lo {
+				continue
+			}
+			if m.Start < lo {
+				m.Start = lo
+			}
+			if m.End > hi {
+				m.End = hi
+			}
+			rs = append(rs, run[V]{m.Start, m.End})
+		}
+		return rs
+	}
+
+	var in []V
+	it, _ := s.inner.Iterate()
+	for v, ok := it.Next(); ok && v <= hi; v, ok = it.Next() {
+		if v >= lo {
+			in = append(in, v)
+		}
+	}
+	return runs(in)
+}
+
+// runs coalesces ascending, distinct values.
+func runs[V bitset.Value](vals []V) (rs []run[V]) {
+	for _, v := range vals {
+		if n := len(rs); n > 0 && rs[n-1].hi+1 == v {
+			rs[n-1].hi = v
+		} else {
+			rs = append(rs, run[V]{v, v})
+		}
+	}
+	return rs
+}
+
+// gaps returns the runs within [lo, hi] which aren't covered by rs, which must be ascending and within it.
+func gaps[V bitset.Value](lo, hi V, rs []run[V]) (out []run[V]) {
+	next := lo
+	for _, r := range rs {
+		if r.lo > next {
+			out = append(out, run[V]{next, r.lo - 1})
+		}
+		if r.hi == hi {
+			return out
+		}
+		next = r.hi + 1
+	}
+	return append(out, run[V]{next, hi})
+}
+
+// record applies a modification, as a new version, unless it's empty.
+func (s *Bitset[V]) record(set, unset []run[V]) {
+	if len(set) == 0 && len(unset) == 0 {
+		return
+	}
+
+	d := delta[V]{version: s.last + 1, prev: s.version(), set: set, unset: unset}
+	s.apply(d.set, d.unset)
+	s.done = append(s.done, d)
+	s.undone = nil
+	s.last = d.version
+}
+
+func (s *Bitset[V]) apply(set, unset []run[V]) {
+	r, ranges := s.inner.(ranger[V])
+	for _, u := range unset {
+		if ranges {
+			r.UnsetRange(u.lo, u.hi)
+		} else {
+			s.inner.Unset(expand(u)...)
+		}
+	}
+	for _, u := range set {
+		if ranges {
+			r.SetRange(u.lo, u.hi)
+		} else {
+			s.inner.Set(expand(u)...)
+		}
+	}
+}
+
+// expand lists the values of r.
+func expand[V bitset.Value](r run[V]) (vals []V) {
+	for v := r.lo; ; v++ {
+		vals = append(vals, v)
+		if v == r.hi {
+			return vals
+		}
+	}
+}
+
+// Undo reverts the latest modification, reporting whether there was one to revert.
+func (s *Bitset[V]) Undo() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.done) == 0 {
+		return false
+	}
+	d := s.done[len(s.done)-1]
+	s.done = s.done[:len(s.done)-1]
+
+	s.apply(d.unset, d.set)
+	s.undone = append(s.undone, d)
+	return true
+}
+
+// Redo reapplies the latest modification undone, reporting whether there was one.
+func (s *Bitset[V]) Redo() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.undone) == 0 {
+		return false
+	}
+	d := s.undone[len(s.undone)-1]
+	s.undone = s.undone[:len(s.undone)-1]
+
+	s.apply(d.set, d.unset)
+	s.done = append(s.done, d)
+	return true
+}
+
+// At returns the members as of version, which must be the current one, one which can be returned to by Undo, or
+// one which can be redone. The result is immutable, and built from the current members, so takes time in
+// proportion to them.
+func (s *Bitset[V]) At(version uint64) (persistent.Bitset[V], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current := persistent.New(iterable.Values[V](s.inner)...)
+	if version == s.version() {
+		return current, nil
+	}
+
+	// back through the applied versions
+	view := current
+	for i := len(s.done) - 1; i >= 0; i-- {
+		d := s.done[i]
+		view = revise(view, d.unset, d.set)
+		if d.prev == version {
+			return view, nil
+		}
+	}
+
+	// forward through the undone ones
+	view = current
+	for i := len(s.undone) - 1; i >= 0; i-- {
+		d := s.undone[i]
+		view = revise(view, d.set, d.unset)
+		if d.version == version {
+			return view, nil
+		}
+	}
+
+	if version < s.base {
+		return persistent.Bitset[V]{}, ErrCompacted
+	}
+	return persistent.Bitset[V]{}, ErrUnknownVersion
+}
+
+// revise returns a version of view with the runs set and unset.
+func revise[V bitset.Value](view persistent.Bitset[V], set, unset []run[V]) persistent.Bitset[V] {
+	view, _ = view.Update(func(tx bitset.Tx[V]) error {
+		for _, r := range unset {
+			tx.UnsetRange(r.lo, r.hi)
+		}
+		for _, r := range set {
+			tx.SetRange(r.lo, r.hi)
+		}
+		return nil
+	})
+	return view
+}
+
+// Compact discards the history before version, so that neither Undo nor At can go back beyond it.
+// Versions which can be redone are kept.
+func (s *Bitset[V]) Compact(before uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	n := 0
+	for n < len(s.done) && s.done[n].prev < before {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	s.base = s.done[n-1].version
+	s.done = append([]delta[V](nil), s.done[n:]...)
+}
+
+// Len is the number of versions in the history, which can be undone or redone.
+func (s *Bitset[V]) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.done) + len(s.undone)
+}
+
+var (
+	_ bitset.Bitset[uint]     = (*Bitset[uint])(nil)
+	_ iterable.Iterable[uint] = (*Bitset[uint])(nil)
+)
@@ -0,0 +1,137 @@
+package versioned
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/hier"
+	"github.com/zblach/go-bitset/iterable"
+	rangeset "github.com/zblach/go-bitset/sparse/range"
+)
+
+func TestUndoRedo(t *testing.T) {
+	inner := rangeset.New[uint]()
+	inner.Set(1)
+	s := New[uint](inner)
+	assert.Equal(t, uint64(0), s.Version())
+
+	s.Set(2, 3, 1)
+	s.Set(3) // no change, so no version
+	s.Unset(1)
+	s.Clear()
+	assert.Equal(t, uint64(3), s.Version())
+	assert.Empty(t, iterable.Values[uint](s))
+
+	assert.True(t, s.Undo())
+	assert.Equal(t, []uint{2, 3}, iterable.Values[uint](s))
+	assert.True(t, s.Undo())
+	assert.Equal(t, uint64(1), s.Version())
+	assert.Equal(t, []uint{1, 2, 3}, iterable.Values[uint](s))
+
+	assert.True(t, s.Redo())
+	assert.Equal(t, uint64(2), s.Version())
+	assert.Equal(t, []uint{2, 3}, iterable.Values[uint](s))
+
+	// a new version discards what could have been redone
+	s.Set(9)
+	assert.Equal(t, uint64(4), s.Version(), "versions aren't reused")
+	assert.False(t, s.Redo())
+	assert.Equal(t, []uint{2, 3, 9}, iterable.Values[uint](inner))
+
+	for s.Undo() {
+	}
+	assert.Equal(t, uint64(0), s.Version())
+	assert.Equal(t, []uint{1}, iterable.Values[uint](s))
+	assert.Equal(t, 3, s.Len())
+}
+
+func TestAt(t *testing.T) {
+	s := New[uint](bits.New[uint64, uint](0))
+	s.Set(1, 2) // 1
+	s.Unset(1)  // 2
+	s.Set(5000) // 3
+	s.Unset(2)  // 4
+	assert.True(t, s.Undo())
+
+	for version, want := range map[uint64][]uint{
+		0: {},
+		1: {1, 2},
+		2: {2},
+		3: {2, 5000},
+		4: {5000}, // can be redone
+	} {
+		view, err := s.At(version)
+		assert.NoError(t, err)
+		assert.Equal(t, want, iterable.Values[uint](view), "version %d", version)
+	}
+
+	_, err := s.At(9)
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+
+	view, _ := s.At(1)
+	s.Clear()
+	assert.True(t, view.Get(1), "views are unaffected by later modifications")
+
+	s.Compact(2)
+	_, err = s.At(1)
+	assert.ErrorIs(t, err, ErrCompacted)
+	view, err = s.At(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2}, iterable.Values[uint](view))
+
+	for s.Undo() {
+	}
+	assert.Equal(t, uint64(2), s.Version(), "history before 2 is gone")
+	assert.Equal(t, []uint{2}, iterable.Values[uint](s))
+}
+
+func TestRanges(t *testing.T) {
+	for name, inner := range map[string]Backend[uint]{
+		"bits":  bits.New[uint64, uint](0), // navigates
+		"range": rangeset.New[uint](),      // has runs
+		"hier":  hier.New[uint](0),         // neither, nor ranges of its own
+	} {
+		s := New[uint](inner)
+		s.Set(5, 6, 8)
+
+		s.SetRange(0, 9)
+		assert.Equal(t, uint64(2), s.Version(), name)
+		assert.Equal(t, []run[uint]{{0, 4}, {7, 7}, {9, 9}}, s.done[1].set, "%s: only the gaps are journalled", name)
+		s.SetRange(2, 7) // no change
+		assert.Equal(t, uint64(2), s.Version(), name)
+
+		s.UnsetRange(3, 1000)
+		assert.Equal(t, []run[uint]{{3, 9}}, s.done[2].unset, name)
+		s.UnsetRange(500, 600) // no change
+		s.SetRange(9, 5)       // empty
+		assert.Equal(t, uint64(3), s.Version(), name)
+		assert.Equal(t, []uint{0, 1, 2}, iterable.Values[uint](s), name)
+
+		s.Set(20, 21, 22, 30)
+		s.Clear()
+		assert.Equal(t, []run[uint]{{0, 2}, {20, 22}, {30, 30}}, s.done[4].unset, "%s: cleared as runs", name)
+
+		for version, want := range map[uint64][]uint{
+			0: {},
+			1: {5, 6, 8},
+			2: {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			3: {0, 1, 2},
+			4: {0, 1, 2, 20, 21, 22, 30},
+		} {
+			view, err := s.At(version)
+			assert.NoError(t, err, name)
+			assert.Equal(t, want, iterable.Values[uint](view), "%s: version %d", name, version)
+		}
+
+		assert.True(t, s.Undo(), name)
+		assert.True(t, s.Undo(), name)
+		assert.True(t, s.Undo(), name)
+		assert.Equal(t, []uint{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, iterable.Values[uint](s), name)
+		assert.True(t, s.Undo(), name)
+		assert.Equal(t, []uint{5, 6, 8}, iterable.Values[uint](s), name)
+		assert.True(t, s.Redo(), name)
+		assert.True(t, s.Redo(), name)
+		assert.Equal(t, []uint{0, 1, 2}, iterable.Values[uint](s), name)
+	}
+}
@@ -0,0 +1,107 @@
+package watch
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/zblach/go-bitset"
+)
+
+// Subscription is a subscriber to a Set's changes, by callback or by channel.
+type Subscription[V bitset.Value] struct {
+	// C receives the changes of a subscription made by Watch or WatchRange. It's nil for callbacks.
+	C <-chan Change[V]
+
+	set *Set[V]
+	fn  func(Change[V])
+
+	ch     chan Change[V]
+	policy Policy
+
+	ranged bool
+	lo, hi V
+
+	dropped uint64
+	done    chan struct{} // closed by Close, to release a blocked writer
+	once    sync.Once
+}
+
+func newChannel[V bitset.Value](buffer int, policy Policy) *Subscription[V] {
+	ch := make(chan Change[V], buffer)
+	return &Subscription[V]{C: ch, ch: ch, policy: policy}
+}
+
+// Close unsubscribes, and closes C, if any. No further changes are delivered, though a callback may still be running
+// with one when Close returns.
+//
+// A callback may close its own subscription, or any other callback's. Closing a channel subscription takes the
+// set's lock, so it mustn't be done from a callback, but may be while a writer is blocked on C.
+func (sub *Subscription[V]) Close() {
+	sub.once.Do(func() {
+		close(sub.done)
+		if sub.ch != nil {
+			sub.set.remove(sub)
+		}
+	})
+}
+
+func (sub *Subscription[V]) closed() bool {
+	select {
+	case <-sub.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Dropped is the number of changes discarded because C was full, under the Drop policy.
+func (sub *Subscription[V]) Dropped() uint64 {
+	return atomic.LoadUint64(&sub.dropped)
+}
+
+// filter trims c to the subscription's range, reporting whether there's anything left.
+func (sub *Subscription[V]) filter(c Change[V]) (Change[V], bool) {
+	if !sub.ranged {
+		return c, true
+	}
+	c.Set, c.Unset = sub.within(c.Set), sub.within(c.Unset)
+	return c, len(c.Set) > 0 || len(c.Unset) > 0 || c.Cleared
+}
+
+// within returns the ascending values which are in the subscription's range.
+func (sub *Subscription[V]) within(vals []V) []V {
+	lo := 0
+	for lo < len(vals) && vals[lo] < sub.lo {
+		lo++
+	}
+	hi := lo
+	for hi < len(vals) && vals[hi] <= sub.hi {
+		hi++
+	}
+	if lo == hi {
+		return nil
+	}
+	return vals[lo:hi:hi]
+}
+
+// deliver hands c to the subscriber. The set's lock is held.
+func (sub *Subscription[V]) deliver(c Change[V]) {
+	if sub.fn != nil {
+		sub.fn(c)
+		return
+	}
+
+	if sub.policy == Drop {
+		select {
+		case sub.ch <- c:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+		return
+	}
+
+	select {
+	case sub.ch <- c:
+	case <-sub.done:
+	}
+}
@@ -0,0 +1,178 @@
+// Package watch reports the values which change in a set, to subscribers.
+//
+// A Set wraps another, and every modification made through it is delivered to its subscribers as a Change, listing
+// the values which actually flipped. Modifications made to the wrapped set directly aren't seen.
+//
+// Writes through a Set are serialized by its lock, and changes are delivered while it's still held, after the wrapped
+// set has been modified. So every subscriber receives changes in the order they were made, and a callback has been
+// run by the time the write returns. Callbacks must therefore be quick, and mustn't write or subscribe to the Set themselves.
+//
+// Channel subscribers, from Watch, are given a buffer, and a Policy decides what happens once it's full: Block makes
+// writers wait for the consumer, and Drop discards the change, counting it, so the consumer can tell that it missed
+// some and should resynchronize from the set.
+package watch
+
+import (
+	"sync"
+
+	"github.com/zblach/go-bitset"
+	"github.com/zblach/go-bitset/internal/flip"
+	"github.com/zblach/go-bitset/iterable"
+)
+
+// Change lists the values which a single write set and unset, in ascending order.
+type Change[V bitset.Value] struct {
+	Set   []V
+	Unset []V
+
+	// Cleared is set when the change came from Clear. Unset then lists the members removed, if the wrapped set is an
+	// iterable.Iterable, as every backend is; otherwise, it's empty.
+	Cleared bool
+}
+
+// Policy decides what happens when a channel subscriber's buffer is full.
+type Policy int
+
+const (
+	// Block waits for the consumer to make room. Writes to the set, and to its other subscribers, are held up.
+	Block Policy = iota
+	// Drop discards the change, and counts it in Dropped.
+	Drop
+)
+
+// Set is a set which notifies its subscribers of changes made through it.
+type Set[V bitset.Value] struct {
+	lock *sync.Mutex
+
+	inner bitset.Bitset[V]
+	subs  []*Subscription[V]
+}
+
+// New wraps inner. It should only be modified through the returned Set from then on.
+func New[V bitset.Value](inner bitset.Bitset[V]) *Set[V] {
+	return &Set[V]{
+		lock:  &sync.Mutex{},
+		inner: inner,
+	}
+}
+
+// Unwrap returns the wrapped set, for reading.
+func (s *Set[V]) Unwrap() bitset.Bitset[V] {
+	return s.inner
+}
+
+// Get implements bitset.Bitset
+func (s *Set[V]) Get(index V) bool {
+	return s.inner.Get(index)
+}
+
+// Set implements bitset.Bitset, notifying subscribers of the values which weren't already members.
+func (s *Set[V]) Set(indices ...V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	changed := flip.Changed(s.inner.Get, indices, false)
+	if len(changed) == 0 {
+		return
+	}
+	s.inner.Set(changed...)
+	s.publish(Change[V]{Set: changed})
+}
+
+// Unset implements bitset.Bitset, notifying subscribers of the values which were members.
+func (s *Set[V]) Unset(indices ...V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	changed := flip.Changed(s.inner.Get, indices, true)
+	if len(changed) == 0 {
+		return
+	}
+	s.inner.Unset(changed...)
+	s.publish(Change[V]{Unset: changed})
+}
+
+// Clear implements bitset.Bitset, notifying subscribers of the members removed.
+func (s *Set[V]) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var members []V
+	if it, ok := s.inner.(iterable.Iterable[V]); ok {
+		members = iterable.Values(it)
+	}
+	s.inner.Clear()
+	s.publish(Change[V]{Unset: members, Cleared: true})
+}
+
+// publish delivers c to every subscriber, in the order they subscribed.
+func (s *Set[V]) publish(c Change[V]) {
+	for _, sub := range s.subs {
+		if f, ok := sub.filter(c); ok && !sub.closed() {
+			sub.deliver(f)
+		}
+	}
+	s.prune()
+}
+
+// prune drops the callbacks which were closed, which can't remove themselves while a change is being delivered.
+func (s *Set[V]) prune() {
+	n := 0
+	for _, sub := range s.subs {
+		if !sub.closed() {
+			s.subs[n] = sub
+			n++
+		}
+	}
+	for i := n; i < len(s.subs); i++ {
+		s.subs[i] = nil
+	}
+	s.subs = s.subs[:n]
+}
+
+// Subscribe calls fn with every change, until the subscription is closed.
+func (s *Set[V]) Subscribe(fn func(Change[V])) *Subscription[V] {
+	return s.add(&Subscription[V]{fn: fn})
+}
+
+// SubscribeRange calls fn with the changes to values in [lo, hi], until the subscription is closed.
+// Changes are trimmed to the range, and those with nothing left in it are skipped.
+func (s *Set[V]) SubscribeRange(lo, hi V, fn func(Change[V])) *Subscription[V] {
+	return s.add(&Subscription[V]{fn: fn, ranged: true, lo: lo, hi: hi})
+}
+
+// Watch sends every change on the subscription's channel, C, which holds up to buffer of them.
+// policy decides what happens once it's full. C is closed when the subscription is.
+func (s *Set[V]) Watch(buffer int, policy Policy) *Subscription[V] {
+	return s.add(newChannel[V](buffer, policy))
+}
+
+// WatchRange is Watch, for the changes to values in [lo, hi], filtered as by SubscribeRange.
+func (s *Set[V]) WatchRange(lo, hi V, buffer int, policy Policy) *Subscription[V] {
+	sub := newChannel[V](buffer, policy)
+	sub.ranged, sub.lo, sub.hi = true, lo, hi
+	return s.add(sub)
+}
+
+func (s *Set[V]) add(sub *Subscription[V]) *Subscription[V] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.prune()
+	sub.set = s
+	sub.done = make(chan struct{})
+	s.subs = append(s.subs, sub)
+	return sub
+}
+
+// remove unsubscribes sub, closing its channel.
+func (s *Set[V]) remove(sub *Subscription[V]) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.prune()
+	// no more sends can happen, as they're made with the lock held
+	close(sub.ch)
+}
+
+var _ bitset.Bitset[uint] = (*Set[uint])(nil)
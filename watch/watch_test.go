@@ -0,0 +1,100 @@
+package watch
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zblach/go-bitset/dense/bits"
+	"github.com/zblach/go-bitset/dense/fixed"
+)
+
+func TestSubscribe(t *testing.T) {
+	s := New[uint](bits.New[uint64, uint](0))
+	s.Set(1)
+
+	var all, ranged []Change[uint]
+	sub := s.Subscribe(func(c Change[uint]) { all = append(all, c) })
+	s.SubscribeRange(10, 20, func(c Change[uint]) { ranged = append(ranged, c) })
+
+	s.Set(30, 1, 15, 15, 2)
+	s.Unset(1, 100)
+	s.Set(2) // no change
+	s.Clear()
+
+	assert.Equal(t, []Change[uint]{
+		{Set: []uint{2, 15, 30}},
+		{Unset: []uint{1}},
+		{Unset: []uint{2, 15, 30}, Cleared: true},
+	}, all)
+	assert.Equal(t, []Change[uint]{
+		{Set: []uint{15}},
+		{Unset: []uint{15}, Cleared: true},
+	}, ranged)
+
+	sub.Close()
+	s.Set(5)
+	assert.Len(t, all, 3, "closed")
+	assert.Len(t, ranged, 2, "out of range")
+	assert.True(t, s.Unwrap().Get(5))
+}
+
+func TestSubscribe_CloseFromCallback(t *testing.T) {
+	s := New[uint](bits.New[uint64, uint](0))
+
+	var n int
+	var sub *Subscription[uint]
+	sub = s.Subscribe(func(c Change[uint]) {
+		n++
+		sub.Close()
+	})
+	s.Set(1)
+	s.Set(2)
+	assert.Equal(t, 1, n)
+}
+
+func TestWatch_Drop(t *testing.T) {
+	s := New[uint](&fixed.Set64[uint]{})
+	sub := s.WatchRange(0, 9, 1, Drop)
+
+	s.Set(1)
+	s.Set(20) // out of range
+	s.Set(2)  // dropped
+	assert.Equal(t, Change[uint]{Set: []uint{1}}, <-sub.C)
+	assert.Equal(t, uint64(1), sub.Dropped())
+
+	sub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	s.Set(3)
+}
+
+func TestWatch_Block(t *testing.T) {
+	s := New[uint](bits.New[uint64, uint](0))
+	sub := s.Watch(0, Block)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := uint(0); i < 100; i++ {
+			s.Set(i)
+		}
+	}()
+
+	// every change arrives, in order
+	for i := uint(0); i < 100; i++ {
+		assert.Equal(t, Change[uint]{Set: []uint{i}}, <-sub.C)
+	}
+	wg.Wait()
+
+	// closing releases a blocked writer
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Set(1000)
+	}()
+	sub.Close()
+	wg.Wait()
+	assert.True(t, s.Get(1000))
+}